@@ -0,0 +1,243 @@
+// Package anthropic implements provider.ChatCompletionClient for Anthropic's
+// Messages API (https://docs.anthropic.com/en/api/messages), which differs
+// from the OpenAI wire format in its headers, its separate top-level
+// "system" field, and its SSE event framing.
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const (
+	defaultURL       = "https://api.anthropic.com/v1/messages"
+	anthropicVersion = "2023-06-01"
+	defaultMaxTokens = 4096
+	defaultTimeout   = 60 * time.Second
+)
+
+func init() {
+	provider.Register("anthropic", New)
+}
+
+type client struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+	verbose    bool
+}
+
+// New constructs an Anthropic client from cfg.
+func New(cfg provider.Config) provider.ChatCompletionClient {
+	url := cfg.BaseURL
+	if url == "" {
+		url = defaultURL
+	}
+	return &client{
+		url:        url,
+		apiKey:     cfg.APIKey,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		verbose:    cfg.Verbose,
+	}
+}
+
+type wireMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type messagesRequest struct {
+	Model       string        `json:"model"`
+	System      string        `json:"system,omitempty"`
+	Messages    []wireMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        *float64      `json:"top_p,omitempty"`
+	TopK        *int          `json:"top_k,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+type messagesResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// sseEvent mirrors the subset of Anthropic's streaming event payloads we
+// care about: content_block_delta carries the next chunk of text.
+type sseEvent struct {
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+func (c *client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	if len(params.Tools) > 0 {
+		return "", fmt.Errorf("anthropic provider does not yet support tool calling")
+	}
+
+	reqBody := c.buildRequest(params, *messages, false)
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.do(ctx, jsonData)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var parsed messagesResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if parsed.Error != nil {
+		return "", fmt.Errorf("API error (%s): %s", parsed.Error.Type, parsed.Error.Message)
+	}
+
+	var text strings.Builder
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+func (c *client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	if len(params.Tools) > 0 {
+		return fmt.Errorf("anthropic provider does not yet support tool calling")
+	}
+
+	reqBody := c.buildRequest(params, *messages, true)
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.do(ctx, jsonData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if event != "content_block_delta" {
+				continue
+			}
+			var parsed sseEvent
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &parsed); err != nil {
+				if c.verbose {
+					log.Printf("[DEBUG] failed to parse Anthropic SSE chunk: %v", err)
+				}
+				continue
+			}
+			if parsed.Delta.Text != "" && chunk != nil {
+				if err := chunk(parsed.Delta.Text); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+func (c *client) buildRequest(params provider.Params, messages []provider.Message, stream bool) messagesRequest {
+	var system strings.Builder
+	var wireMessages []wireMessage
+	for _, m := range messages {
+		if m.Role == "system" {
+			if system.Len() > 0 {
+				system.WriteString("\n\n")
+			}
+			system.WriteString(m.Content)
+			continue
+		}
+		wireMessages = append(wireMessages, wireMessage{Role: m.Role, Content: m.Content})
+	}
+
+	maxTokens := defaultMaxTokens
+	if params.MaxTokens != nil {
+		maxTokens = *params.MaxTokens
+	}
+
+	return messagesRequest{
+		Model:       params.Model,
+		System:      system.String(),
+		Messages:    wireMessages,
+		MaxTokens:   maxTokens,
+		Temperature: params.Temperature,
+		TopP:        params.TopP,
+		TopK:        params.TopK,
+		Stream:      stream,
+	}
+}
+
+func (c *client) do(ctx context.Context, jsonData []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	if c.verbose {
+		log.Printf("[DEBUG] Anthropic request URL: %s, body size: %d bytes", c.url, len(jsonData))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &provider.ConnError{Err: fmt.Errorf("request timed out after %v", defaultTimeout)}
+		}
+		return nil, &provider.ConnError{Err: fmt.Errorf("failed to send request: %w", err)}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		message := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body))
+		var parsed messagesResponse
+		if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error != nil {
+			message = fmt.Sprintf("HTTP %d - API error (%s): %s", resp.StatusCode, parsed.Error.Type, parsed.Error.Message)
+		}
+		return nil, &provider.HTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: provider.ParseRetryAfter(resp.Header.Get("Retry-After")),
+			Message:    message,
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+// Package azure implements provider.ChatCompletionClient for Azure OpenAI,
+// which uses the same chat-completions wire format as OpenAI but addresses a
+// deployment instead of a model and authenticates with an api-key header.
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+	"github.com/blackopsinc/openrouter-cli/internal/provider/openaicompat"
+)
+
+const (
+	defaultAPIVersion = "2024-02-15-preview"
+	userAgent         = "OpenRouter-CLI/1.0"
+)
+
+func init() {
+	provider.Register("azure", New)
+}
+
+type client struct {
+	inner *openaicompat.Client
+}
+
+// New constructs an Azure OpenAI client. cfg.BaseURL is the resource
+// endpoint (e.g. https://my-resource.openai.azure.com), cfg.Deployment is
+// the deployment name, and cfg.APIVersion defaults to defaultAPIVersion.
+func New(cfg provider.Config) provider.ChatCompletionClient {
+	apiVersion := cfg.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAPIVersion
+	}
+
+	url := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s", cfg.BaseURL, cfg.Deployment, apiVersion)
+	headers := map[string]string{
+		"User-Agent": userAgent,
+		"api-key":    cfg.APIKey,
+	}
+	return &client{inner: openaicompat.New(url, headers, cfg.Verbose)}
+}
+
+func (c *client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	return c.inner.CreateChatCompletion(ctx, params, messages, usage)
+}
+
+func (c *client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	return c.inner.CreateChatCompletionStream(ctx, params, messages, chunk, usage)
+}
@@ -0,0 +1,270 @@
+// Package google implements provider.ChatCompletionClient for the Gemini API
+// (generativelanguage.googleapis.com), which uses its own "contents"/"parts"
+// message shape and an API-key query parameter instead of a header.
+package google
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const (
+	defaultBaseURL = "https://generativelanguage.googleapis.com"
+	apiVersion     = "v1beta"
+	defaultTimeout = 60 * time.Second
+)
+
+func init() {
+	provider.Register("google", New)
+}
+
+type client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	verbose    bool
+}
+
+// New constructs a Gemini client from cfg.
+func New(cfg provider.Config) provider.ChatCompletionClient {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &client{
+		baseURL:    baseURL,
+		apiKey:     cfg.APIKey,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		verbose:    cfg.Verbose,
+	}
+}
+
+type part struct {
+	Text string `json:"text"`
+}
+
+type content struct {
+	Role  string `json:"role"`
+	Parts []part `json:"parts"`
+}
+
+type generationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"topP,omitempty"`
+	TopK            *int     `json:"topK,omitempty"`
+	MaxOutputTokens *int     `json:"maxOutputTokens,omitempty"`
+}
+
+type generateRequest struct {
+	Contents          []content        `json:"contents"`
+	SystemInstruction *content         `json:"systemInstruction,omitempty"`
+	GenerationConfig  generationConfig `json:"generationConfig,omitempty"`
+}
+
+type generateResponse struct {
+	Candidates []struct {
+		Content content `json:"content"`
+	} `json:"candidates"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (c *client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	if len(params.Tools) > 0 {
+		return "", fmt.Errorf("google provider does not yet support tool calling")
+	}
+
+	reqBody := buildRequest(params, *messages)
+	url := fmt.Sprintf("%s/%s/models/%s:generateContent?key=%s", c.baseURL, apiVersion, params.Model, c.apiKey)
+
+	body, err := c.post(ctx, url, reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	var parsed generateResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if parsed.Error != nil {
+		return "", fmt.Errorf("API error: %s", parsed.Error.Message)
+	}
+	if len(parsed.Candidates) == 0 {
+		return "", fmt.Errorf("no response received from the API")
+	}
+	return joinParts(parsed.Candidates[0].Content.Parts), nil
+}
+
+func (c *client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	if len(params.Tools) > 0 {
+		return fmt.Errorf("google provider does not yet support tool calling")
+	}
+
+	reqBody := buildRequest(params, *messages)
+	url := fmt.Sprintf("%s/%s/models/%s:streamGenerateContent?alt=sse&key=%s", c.baseURL, apiVersion, params.Model, c.apiKey)
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.verbose {
+		log.Printf("[DEBUG] Gemini streaming request URL: %s", redactAPIKey(url))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return &provider.ConnError{Err: fmt.Errorf("request timed out after %v", defaultTimeout)}
+		}
+		return &provider.ConnError{Err: fmt.Errorf("failed to send request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &provider.HTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: provider.ParseRetryAfter(resp.Header.Get("Retry-After")),
+			Message:    fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
+		}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var parsed generateResponse
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &parsed); err != nil {
+			if c.verbose {
+				log.Printf("[DEBUG] failed to parse Gemini SSE chunk: %v", err)
+			}
+			continue
+		}
+		if parsed.Error != nil {
+			return fmt.Errorf("API error in stream: %s", parsed.Error.Message)
+		}
+		if len(parsed.Candidates) == 0 {
+			continue
+		}
+		text := joinParts(parsed.Candidates[0].Content.Parts)
+		if text != "" && chunk != nil {
+			if err := chunk(text); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+func buildRequest(params provider.Params, messages []provider.Message) generateRequest {
+	var contents []content
+	var system *content
+	for _, m := range messages {
+		role := "user"
+		if m.Role == "assistant" {
+			role = "model"
+		}
+		if m.Role == "system" {
+			system = &content{Role: "system", Parts: []part{{Text: m.Content}}}
+			continue
+		}
+		contents = append(contents, content{Role: role, Parts: []part{{Text: m.Content}}})
+	}
+
+	return generateRequest{
+		Contents:          contents,
+		SystemInstruction: system,
+		GenerationConfig: generationConfig{
+			Temperature:     params.Temperature,
+			TopP:            params.TopP,
+			TopK:            params.TopK,
+			MaxOutputTokens: params.MaxTokens,
+		},
+	}
+}
+
+// redactAPIKey strips the key query parameter from a Gemini request URL
+// before it's written to verbose logs. Gemini authenticates via ?key=...
+// rather than a header, so logging the URL as-is would leak GOOGLE_API_KEY to
+// stderr on every verbose request.
+func redactAPIKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Del("key")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func joinParts(parts []part) string {
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p.Text)
+	}
+	return b.String()
+}
+
+func (c *client) post(ctx context.Context, url string, reqBody generateRequest) ([]byte, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.verbose {
+		log.Printf("[DEBUG] Gemini request URL: %s, body size: %d bytes", redactAPIKey(url), len(jsonData))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &provider.ConnError{Err: fmt.Errorf("request timed out after %v", defaultTimeout)}
+		}
+		return nil, &provider.ConnError{Err: fmt.Errorf("failed to send request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &provider.HTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: provider.ParseRetryAfter(resp.Header.Get("Retry-After")),
+			Message:    fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,42 @@
+// Package lmstudio implements provider.ChatCompletionClient for LM Studio's
+// OpenAI-compatible local server.
+package lmstudio
+
+import (
+	"context"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+	"github.com/blackopsinc/openrouter-cli/internal/provider/openaicompat"
+)
+
+const (
+	defaultURL = "http://10.8.0.22:1234/v1/chat/completions"
+	userAgent  = "OpenRouter-CLI/1.0"
+)
+
+func init() {
+	provider.Register("lmstudio", New)
+}
+
+type client struct {
+	inner *openaicompat.Client
+}
+
+// New constructs an LM Studio client from cfg. LM Studio doesn't require
+// authentication, so no Authorization header is sent.
+func New(cfg provider.Config) provider.ChatCompletionClient {
+	url := cfg.BaseURL
+	if url == "" {
+		url = defaultURL
+	}
+	headers := map[string]string{"User-Agent": userAgent}
+	return &client{inner: openaicompat.New(url, headers, cfg.Verbose)}
+}
+
+func (c *client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	return c.inner.CreateChatCompletion(ctx, params, messages, usage)
+}
+
+func (c *client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	return c.inner.CreateChatCompletionStream(ctx, params, messages, chunk, usage)
+}
@@ -0,0 +1,234 @@
+// Package ollama implements provider.ChatCompletionClient for Ollama's
+// native /api/chat endpoint, which uses newline-delimited JSON rather than
+// SSE and has no concept of tool calls.
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const (
+	defaultURL     = "http://localhost:11434/api/chat"
+	userAgent      = "OpenRouter-CLI/1.0"
+	defaultTimeout = 60 * time.Second
+)
+
+func init() {
+	provider.Register("ollama", New)
+}
+
+type client struct {
+	url        string
+	httpClient *http.Client
+	verbose    bool
+}
+
+// New constructs an Ollama client from cfg.
+func New(cfg provider.Config) provider.ChatCompletionClient {
+	url := cfg.BaseURL
+	if url == "" {
+		url = defaultURL
+	}
+	return &client{
+		url:        url,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		verbose:    cfg.Verbose,
+	}
+}
+
+type wireMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []wireMessage          `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+type chatResponse struct {
+	Message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"message"`
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+
+	// Usage accounting, present on the final message only (done:true) - see
+	// https://github.com/ollama/ollama/blob/main/docs/api.md#response-10.
+	PromptEvalCount int   `json:"prompt_eval_count,omitempty"`
+	EvalCount       int   `json:"eval_count,omitempty"`
+	TotalDuration   int64 `json:"total_duration,omitempty"`
+	EvalDuration    int64 `json:"eval_duration,omitempty"`
+}
+
+// usageFrom builds a provider.Usage from a final (done:true) chatResponse.
+func usageFrom(parsed chatResponse) provider.Usage {
+	return provider.Usage{
+		PromptTokens:     parsed.PromptEvalCount,
+		CompletionTokens: parsed.EvalCount,
+		TotalTokens:      parsed.PromptEvalCount + parsed.EvalCount,
+		EvalDuration:     time.Duration(parsed.EvalDuration) * time.Nanosecond,
+	}
+}
+
+func (c *client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	reqBody := c.buildRequest(params, *messages, false)
+	body, err := c.send(ctx, reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse Ollama response: %w", err)
+	}
+	if parsed.Error != "" {
+		return "", fmt.Errorf("Ollama error: %s", parsed.Error)
+	}
+	if usage != nil {
+		*usage = usageFrom(parsed)
+	}
+	return parsed.Message.Content, nil
+}
+
+func (c *client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	reqBody := c.buildRequest(params, *messages, true)
+
+	resp, err := c.do(ctx, reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		var parsed chatResponse
+		if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+			if c.verbose {
+				log.Printf("[DEBUG] failed to parse Ollama chunk: %v", err)
+			}
+			continue
+		}
+		if parsed.Error != "" {
+			return fmt.Errorf("Ollama error in stream: %s", parsed.Error)
+		}
+		if parsed.Message.Content != "" && chunk != nil {
+			if err := chunk(parsed.Message.Content); err != nil {
+				return err
+			}
+		}
+		if parsed.Done {
+			if usage != nil {
+				*usage = usageFrom(parsed)
+			}
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read Ollama stream: %w", err)
+	}
+	return nil
+}
+
+func (c *client) buildRequest(params provider.Params, messages []provider.Message, stream bool) chatRequest {
+	wireMessages := make([]wireMessage, len(messages))
+	for i, m := range messages {
+		wireMessages[i] = wireMessage{Role: m.Role, Content: m.Content}
+	}
+
+	options := map[string]interface{}{}
+	if params.Temperature != nil {
+		options["temperature"] = *params.Temperature
+	}
+	if params.TopP != nil {
+		options["top_p"] = *params.TopP
+	}
+	if params.TopK != nil {
+		options["top_k"] = *params.TopK
+	}
+	if params.MaxTokens != nil {
+		options["num_predict"] = *params.MaxTokens
+	}
+	if params.Seed != nil {
+		options["seed"] = *params.Seed
+	}
+	for k, v := range params.Extra {
+		options[k] = v
+	}
+
+	return chatRequest{
+		Model:    params.Model,
+		Messages: wireMessages,
+		Stream:   stream,
+		Options:  options,
+	}
+}
+
+func (c *client) send(ctx context.Context, reqBody chatRequest) ([]byte, error) {
+	resp, err := c.do(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (c *client) do(ctx context.Context, reqBody chatRequest) (*http.Response, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if c.verbose {
+		log.Printf("[DEBUG] Ollama request URL: %s, body size: %d bytes", c.url, len(jsonData))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &provider.ConnError{Err: fmt.Errorf("request timed out after %v", defaultTimeout)}
+		}
+		return nil, &provider.ConnError{Err: fmt.Errorf("failed to send request: %w", err)}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		message := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body))
+		var parsed chatResponse
+		if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error != "" {
+			message = fmt.Sprintf("HTTP %d - Ollama error: %s", resp.StatusCode, parsed.Error)
+		}
+		return nil, &provider.HTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: provider.ParseRetryAfter(resp.Header.Get("Retry-After")),
+			Message:    message,
+		}
+	}
+	return resp, nil
+}
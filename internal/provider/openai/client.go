@@ -0,0 +1,44 @@
+// Package openai implements provider.ChatCompletionClient for the plain
+// OpenAI API.
+package openai
+
+import (
+	"context"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+	"github.com/blackopsinc/openrouter-cli/internal/provider/openaicompat"
+)
+
+const (
+	defaultURL = "https://api.openai.com/v1/chat/completions"
+	userAgent  = "OpenRouter-CLI/1.0"
+)
+
+func init() {
+	provider.Register("openai", New)
+}
+
+type client struct {
+	inner *openaicompat.Client
+}
+
+// New constructs an OpenAI client from cfg.
+func New(cfg provider.Config) provider.ChatCompletionClient {
+	url := cfg.BaseURL
+	if url == "" {
+		url = defaultURL
+	}
+	headers := map[string]string{"User-Agent": userAgent}
+	if cfg.APIKey != "" {
+		headers["Authorization"] = "Bearer " + cfg.APIKey
+	}
+	return &client{inner: openaicompat.New(url, headers, cfg.Verbose)}
+}
+
+func (c *client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	return c.inner.CreateChatCompletion(ctx, params, messages, usage)
+}
+
+func (c *client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	return c.inner.CreateChatCompletionStream(ctx, params, messages, chunk, usage)
+}
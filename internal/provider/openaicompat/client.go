@@ -0,0 +1,415 @@
+// Package openaicompat implements the shared OpenAI chat-completions wire
+// format (request/response JSON shape, SSE streaming, tool-call deltas) used
+// by OpenRouter, LM Studio, plain OpenAI, and Azure OpenAI. Each of those
+// provider packages is a thin wrapper that supplies the right URL and
+// headers and delegates here.
+package openaicompat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const defaultTimeout = 60 * time.Second
+
+// Client is a configurable OpenAI-compatible chat-completions client. URL is
+// the full chat/completions endpoint and Headers are applied to every
+// request (Authorization, api-key, Referer, ...).
+type Client struct {
+	URL        string
+	Headers    map[string]string
+	HTTPClient *http.Client
+	Verbose    bool
+}
+
+// New returns a Client pointed at url with the given headers.
+func New(url string, headers map[string]string, verbose bool) *Client {
+	return &Client{
+		URL:        url,
+		Headers:    headers,
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+		Verbose:    verbose,
+	}
+}
+
+type chatRequest struct {
+	Model          string              `json:"model"`
+	Messages       []wireMessage       `json:"messages"`
+	Stream         bool                `json:"stream,omitempty"`
+	StreamOptions  *streamOptions      `json:"stream_options,omitempty"`
+	Temperature    *float64            `json:"temperature,omitempty"`
+	TopP           *float64            `json:"top_p,omitempty"`
+	TopK           *int                `json:"top_k,omitempty"`
+	MaxTokens      *int                `json:"max_tokens,omitempty"`
+	Presence       *float64            `json:"presence_penalty,omitempty"`
+	Frequency      *float64            `json:"frequency_penalty,omitempty"`
+	Stop           []string            `json:"stop,omitempty"`
+	Seed           *int                `json:"seed,omitempty"`
+	ResponseFormat *wireResponseFormat `json:"response_format,omitempty"`
+	Tools          []wireTool          `json:"tools,omitempty"`
+	ToolChoice     interface{}         `json:"tool_choice,omitempty"`
+}
+
+// streamOptions asks an OpenAI-compatible endpoint to emit a final SSE chunk
+// carrying the request's token usage, since that's otherwise omitted from a
+// streamed response.
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// wireResponseFormat mirrors the OpenAI request shape for response_format,
+// e.g. {"type": "json_object"}.
+type wireResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type wireUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type wireMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []wireToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+	Name       string         `json:"name,omitempty"`
+}
+
+type wireTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Parameters  json.RawMessage `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+type wireToolCall struct {
+	Index    int    `json:"index,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Delta struct {
+			Content   string         `json:"content"`
+			ToolCalls []wireToolCall `json:"tool_calls"`
+		} `json:"delta"`
+		Message struct {
+			Content   string         `json:"content"`
+			ToolCalls []wireToolCall `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *wireUsage `json:"usage,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// CreateChatCompletion implements provider.ChatCompletionClient.
+func (c *Client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	var total provider.Usage
+	for {
+		var round provider.Usage
+		content, toolCalls, err := c.doRequest(ctx, params, *messages, false, nil, &round)
+		if err != nil {
+			return "", err
+		}
+		accumulate(&total, round)
+		if len(toolCalls) == 0 {
+			if usage != nil {
+				*usage = total
+			}
+			return content, nil
+		}
+		if err := runTools(params, messages, content, toolCalls); err != nil {
+			return "", err
+		}
+	}
+}
+
+// CreateChatCompletionStream implements provider.ChatCompletionClient.
+func (c *Client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	var total provider.Usage
+	for {
+		var round provider.Usage
+		content, toolCalls, err := c.doRequest(ctx, params, *messages, true, chunk, &round)
+		if err != nil {
+			return err
+		}
+		accumulate(&total, round)
+		if len(toolCalls) == 0 {
+			if usage != nil {
+				*usage = total
+			}
+			return nil
+		}
+		if err := runTools(params, messages, content, toolCalls); err != nil {
+			return err
+		}
+	}
+}
+
+// accumulate adds round's token counts onto total, so a multi-round
+// tool-calling loop reports its summed usage rather than just the last round.
+func accumulate(total *provider.Usage, round provider.Usage) {
+	total.PromptTokens += round.PromptTokens
+	total.CompletionTokens += round.CompletionTokens
+	total.TotalTokens += round.TotalTokens
+}
+
+// runTools appends the assistant turn that requested tool calls plus each
+// tool's result to *messages, so the next round trip carries the answers.
+func runTools(params provider.Params, messages *[]provider.Message, content string, toolCalls []provider.ToolCall) error {
+	*messages = append(*messages, provider.Message{Role: "assistant", Content: content, ToolCalls: toolCalls})
+	if params.RunTool == nil {
+		return fmt.Errorf("model requested %d tool call(s) but no tool runner is configured", len(toolCalls))
+	}
+	for _, call := range toolCalls {
+		result, err := params.RunTool(call)
+		if err != nil {
+			result = fmt.Sprintf("error: %v", err)
+		}
+		*messages = append(*messages, provider.Message{Role: "tool", ToolCallID: call.ID, Name: call.Name, Content: result})
+	}
+	return nil
+}
+
+// doRequest performs one round trip (streaming or not) and returns the
+// assistant content plus any tool calls it requested. When stream is true
+// and chunk is non-nil, chunk is invoked with each content delta as it
+// arrives. usage is always filled in when the response reports it.
+func (c *Client) doRequest(ctx context.Context, params provider.Params, messages []provider.Message, stream bool, chunk func(string) error, usage *provider.Usage) (string, []provider.ToolCall, error) {
+	reqBody := chatRequest{
+		Model:       params.Model,
+		Messages:    toWireMessages(messages),
+		Stream:      stream,
+		Temperature: params.Temperature,
+		TopP:        params.TopP,
+		TopK:        params.TopK,
+		MaxTokens:   params.MaxTokens,
+		Presence:    params.PresencePenalty,
+		Frequency:   params.FrequencyPenalty,
+		Stop:        params.Stop,
+		Seed:        params.Seed,
+		Tools:       toWireTools(params.Tools),
+		ToolChoice:  params.ToolChoice,
+	}
+	if params.ResponseFormat != "" {
+		reqBody.ResponseFormat = &wireResponseFormat{Type: params.ResponseFormat}
+	}
+	if stream {
+		reqBody.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if c.Verbose {
+		log.Printf("[DEBUG] %s request body size: %d bytes", c.URL, len(jsonData))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", nil, &provider.ConnError{Err: fmt.Errorf("request timed out after %v", defaultTimeout)}
+		}
+		return "", nil, &provider.ConnError{Err: fmt.Errorf("failed to send request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		message := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body))
+		var parsed chatResponse
+		if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error != nil {
+			message = fmt.Sprintf("HTTP %d - API error (%s): %s", resp.StatusCode, parsed.Error.Type, parsed.Error.Message)
+		}
+		return "", nil, &provider.HTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: provider.ParseRetryAfter(resp.Header.Get("Retry-After")),
+			Message:    message,
+		}
+	}
+
+	if !stream {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		var parsed chatResponse
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return "", nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if parsed.Error != nil {
+			return "", nil, fmt.Errorf("API error (%s): %s", parsed.Error.Type, parsed.Error.Message)
+		}
+		if len(parsed.Choices) == 0 {
+			return "", nil, fmt.Errorf("no response received from the API")
+		}
+		if parsed.Usage != nil && usage != nil {
+			*usage = provider.Usage{
+				PromptTokens:     parsed.Usage.PromptTokens,
+				CompletionTokens: parsed.Usage.CompletionTokens,
+				TotalTokens:      parsed.Usage.TotalTokens,
+			}
+		}
+		choice := parsed.Choices[0]
+		if chunk != nil && choice.Message.Content != "" {
+			if err := chunk(choice.Message.Content); err != nil {
+				return "", nil, err
+			}
+		}
+		return choice.Message.Content, fromWireToolCalls(choice.Message.ToolCalls), nil
+	}
+
+	return c.consumeSSE(resp.Body, chunk, usage)
+}
+
+// consumeSSE parses the `data: {...}` SSE stream and accumulates both
+// assistant content and any fragmented tool_calls deltas (keyed by index).
+// The final chunk of a request made with stream_options.include_usage carries
+// usage alongside an empty choices array, so that's checked before the
+// choices-empty continue below.
+func (c *Client) consumeSSE(body io.Reader, chunk func(string) error, usage *provider.Usage) (string, []provider.ToolCall, error) {
+	scanner := bufio.NewScanner(body)
+	var fullContent strings.Builder
+	calls := map[int]*wireToolCall{}
+	var order []int
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		if data == "[DONE]" {
+			break
+		}
+
+		var parsed chatResponse
+		if err := json.Unmarshal([]byte(data), &parsed); err != nil {
+			continue
+		}
+		if parsed.Error != nil {
+			return "", nil, fmt.Errorf("API error in stream (%s): %s", parsed.Error.Type, parsed.Error.Message)
+		}
+		if parsed.Usage != nil && usage != nil {
+			*usage = provider.Usage{
+				PromptTokens:     parsed.Usage.PromptTokens,
+				CompletionTokens: parsed.Usage.CompletionTokens,
+				TotalTokens:      parsed.Usage.TotalTokens,
+			}
+		}
+		if len(parsed.Choices) == 0 {
+			continue
+		}
+		choice := parsed.Choices[0]
+
+		if choice.Delta.Content != "" {
+			fullContent.WriteString(choice.Delta.Content)
+			if chunk != nil {
+				if err := chunk(choice.Delta.Content); err != nil {
+					return "", nil, err
+				}
+			}
+		}
+
+		for _, fragment := range choice.Delta.ToolCalls {
+			existing, ok := calls[fragment.Index]
+			if !ok {
+				existing = &wireToolCall{Index: fragment.Index, ID: fragment.ID, Type: fragment.Type}
+				calls[fragment.Index] = existing
+				order = append(order, fragment.Index)
+			}
+			if fragment.ID != "" {
+				existing.ID = fragment.ID
+			}
+			existing.Function.Name += fragment.Function.Name
+			existing.Function.Arguments += fragment.Function.Arguments
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	var toolCalls []wireToolCall
+	for _, idx := range order {
+		toolCalls = append(toolCalls, *calls[idx])
+	}
+	return fullContent.String(), fromWireToolCalls(toolCalls), nil
+}
+
+func toWireMessages(messages []provider.Message) []wireMessage {
+	out := make([]wireMessage, len(messages))
+	for i, m := range messages {
+		out[i] = wireMessage{
+			Role:       m.Role,
+			Content:    m.Content,
+			ToolCallID: m.ToolCallID,
+			Name:       m.Name,
+		}
+		for _, tc := range m.ToolCalls {
+			wtc := wireToolCall{ID: tc.ID}
+			wtc.Function.Name = tc.Name
+			wtc.Function.Arguments = tc.Arguments
+			out[i].ToolCalls = append(out[i].ToolCalls, wtc)
+		}
+	}
+	return out
+}
+
+func toWireTools(tools []provider.Tool) []wireTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]wireTool, len(tools))
+	for i, t := range tools {
+		out[i].Type = "function"
+		out[i].Function.Name = t.Name
+		out[i].Function.Description = t.Description
+		out[i].Function.Parameters = t.Parameters
+	}
+	return out
+}
+
+func fromWireToolCalls(calls []wireToolCall) []provider.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]provider.ToolCall, len(calls))
+	for i, c := range calls {
+		out[i] = provider.ToolCall{ID: c.ID, Name: c.Function.Name, Arguments: c.Function.Arguments}
+	}
+	return out
+}
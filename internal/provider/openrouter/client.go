@@ -0,0 +1,49 @@
+// Package openrouter implements provider.ChatCompletionClient for OpenRouter.
+package openrouter
+
+import (
+	"context"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+	"github.com/blackopsinc/openrouter-cli/internal/provider/openaicompat"
+)
+
+const (
+	defaultURL = "https://openrouter.ai/api/v1/chat/completions"
+	userAgent  = "OpenRouter-CLI/1.0"
+)
+
+func init() {
+	provider.Register("openrouter", New)
+}
+
+// client wraps the shared OpenAI-compatible implementation with OpenRouter's
+// URL, auth header, and attribution headers.
+type client struct {
+	inner *openaicompat.Client
+}
+
+// New constructs an OpenRouter client from cfg.
+func New(cfg provider.Config) provider.ChatCompletionClient {
+	url := cfg.BaseURL
+	if url == "" {
+		url = defaultURL
+	}
+	headers := map[string]string{
+		"User-Agent": userAgent,
+		"Referer":    "https://github.com/blackopsinc/openrouter-cli",
+		"X-Title":    "OpenRouter CLI",
+	}
+	if cfg.APIKey != "" {
+		headers["Authorization"] = "Bearer " + cfg.APIKey
+	}
+	return &client{inner: openaicompat.New(url, headers, cfg.Verbose)}
+}
+
+func (c *client) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	return c.inner.CreateChatCompletion(ctx, params, messages, usage)
+}
+
+func (c *client) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	return c.inner.CreateChatCompletionStream(ctx, params, messages, chunk, usage)
+}
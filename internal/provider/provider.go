@@ -0,0 +1,194 @@
+// Package provider defines the pluggable chat-completion backend interface
+// used by openrouter-cli, plus the registry that main wires a concrete
+// backend from based on LLM_PROVIDER.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Message is a single turn in a conversation, including OpenAI-style tool
+// call plumbing (tool_calls on an assistant turn, tool_call_id/name on the
+// tool result that answers it).
+type Message struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+}
+
+// Tool describes a function the model may call, in the OpenAI tools format.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  []byte // raw JSON schema
+}
+
+// ToolCall is a single invocation the model requested.
+type ToolCall struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// ToolRunner executes a tool call locally and returns the text to feed back
+// to the model as a tool-role message. Clients that support tool calling
+// invoke it in a loop until the model stops requesting tools.
+type ToolRunner func(call ToolCall) (string, error)
+
+// Params holds the provider-agnostic generation request: which model to
+// use, the standard OpenAI-compatible sampling knobs, and anything needed to
+// run a tool-calling loop.
+type Params struct {
+	Model string
+
+	Temperature      *float64
+	TopP             *float64
+	TopK             *int
+	MaxTokens        *int
+	PresencePenalty  *float64
+	FrequencyPenalty *float64
+	Stop             []string
+	Seed             *int
+	ResponseFormat   string
+
+	Tools      []Tool
+	ToolChoice interface{}
+	RunTool    ToolRunner
+
+	// Extra carries provider-specific knobs that don't map onto the fields
+	// above (e.g. Ollama's mirostat/num_ctx/repeat_penalty options bag).
+	Extra map[string]interface{}
+}
+
+// Usage reports a request's token accounting, when the backend provides it.
+// EvalDuration is Ollama's native generation time, letting tokens/sec be
+// computed exactly instead of from wall-clock timing around the request;
+// other providers leave it zero.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	EvalDuration     time.Duration
+}
+
+// Config is the connection configuration a client is constructed with:
+// credentials, endpoint, and anything else needed to reach the backend.
+type Config struct {
+	APIKey  string
+	BaseURL string
+
+	// Azure-specific, ignored by other providers.
+	Deployment string
+	APIVersion string
+
+	Verbose bool
+}
+
+// ChatCompletionClient is implemented once per backend (OpenRouter, Ollama,
+// LM Studio, OpenAI, Anthropic, Google, Azure OpenAI). main selects one from
+// the registry by LLM_PROVIDER and becomes a thin dispatcher over it.
+type ChatCompletionClient interface {
+	// CreateChatCompletion sends messages and returns the final assistant
+	// reply. Implementations that support Params.Tools run the tool-calling
+	// loop internally (via Params.RunTool), appending the resulting
+	// assistant/tool turns to *messages, before returning the final content.
+	// If usage is non-nil and the backend reports token accounting, it's
+	// populated with the request's (or, across a tool-calling loop, the
+	// summed) usage. The Anthropic and Google clients don't parse usage and
+	// leave it unchanged.
+	CreateChatCompletion(ctx context.Context, params Params, messages *[]Message, usage *Usage) (string, error)
+
+	// CreateChatCompletionStream behaves like CreateChatCompletion but
+	// invokes chunk with each piece of assistant content as it arrives.
+	// Across a tool-calling loop chunk receives the content of every round,
+	// including any text the model emits alongside a tool call.
+	CreateChatCompletionStream(ctx context.Context, params Params, messages *[]Message, chunk func(string) error, usage *Usage) error
+}
+
+// Factory constructs a ChatCompletionClient from its connection Config.
+type Factory func(cfg Config) ChatCompletionClient
+
+var registry = map[string]Factory{}
+
+// Register adds a backend factory under name, to be looked up by NewClient.
+// It's called from each provider subpackage's init().
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// NewClient builds the named backend's client from cfg.
+func NewClient(name string, cfg Config) (ChatCompletionClient, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+	return factory(cfg), nil
+}
+
+// Registered reports whether name has a registered factory, so callers can
+// validate LLM_PROVIDER before doing any real work.
+func Registered(name string) bool {
+	_, ok := registry[name]
+	return ok
+}
+
+// HTTPError wraps a non-2xx HTTP response. RetryAfter carries the server's
+// requested backoff from its Retry-After header, whatever the status code
+// (zero if the response didn't specify one), so a retry wrapper can decide
+// how long to wait before trying again.
+type HTTPError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *HTTPError) Error() string { return e.Message }
+
+// ConnError marks a transport-level failure (timeout, DNS, connection
+// refused) as distinct from an HTTP error response, for the same retry
+// classification purpose as HTTPError.
+type ConnError struct {
+	Err error
+}
+
+func (e *ConnError) Error() string { return e.Err.Error() }
+func (e *ConnError) Unwrap() error { return e.Err }
+
+// Retryable reports whether err represents a transient failure worth
+// retrying with backoff: a 429, a 5xx, or a transport-level connection error.
+func Retryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var connErr *ConnError
+	return errors.As(err, &connErr)
+}
+
+// ParseRetryAfter parses an HTTP Retry-After header value, which is either a
+// delay in seconds or an HTTP-date. It returns zero if the header is absent
+// or unparseable, leaving the caller to fall back to its own backoff schedule.
+func ParseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
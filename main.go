@@ -1,616 +1,301 @@
-package main
-
-import (
-	"bufio"
-	"bytes"
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"os"
-	"strings"
-	"time"
-)
-
-const (
-	envAPIKey    = "OPENROUTER_API_KEY"
-	envProvider  = "LLM_PROVIDER"
-	envModel     = "LLM_MODEL"
-	envPrePrompt = "LLM_PRE_PROMPT"
-	envStream    = "LLM_STREAM"
-	envVerbose   = "LLM_VERBOSE"
-	envOllamaURL = "OLLAMA_URL"
-	envLMStudioURL = "LM_STUDIO_URL"
-
-	defaultTimeout = 60 * time.Second
-	defaultModel  = "openai/gpt-oss-20b:free"
-	userAgent      = "OpenRouter-CLI/1.0"
-
-	// Provider URLs
-	openRouterURL  = "https://openrouter.ai/api/v1/chat/completions"
-	defaultOllamaURL = "http://localhost:11434/api/chat"
-	defaultLMStudioURL = "http://10.8.0.22:1234/v1/chat/completions"
-)
-
-// Provider represents the LLM provider type
-type Provider string
-
-const (
-	ProviderOpenRouter Provider = "openrouter"
-	ProviderOllama     Provider = "ollama"
-	ProviderLMStudio   Provider = "lmstudio"
-)
-
-// OpenRouterRequest represents the request body for the OpenRouter API
-type OpenRouterRequest struct {
-	Model    string        `json:"model"`
-	Messages []ChatMessage `json:"messages"`
-	Stream   bool          `json:"stream,omitempty"`
-}
-
-// ChatMessage represents a message in a chat conversation
-type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// OpenRouterResponse represents the response from the OpenRouter API
-type OpenRouterResponse struct {
-	Choices []struct {
-		Delta struct {
-			Content string `json:"content"`
-		} `json:"delta"`
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-	Error *struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-	} `json:"error,omitempty"`
-}
-
-// OllamaResponse represents the response from Ollama's native API
-type OllamaResponse struct {
-	Message struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-	} `json:"message"`
-	Done   bool `json:"done"`
-	Error  string `json:"error,omitempty"`
-}
-
-func main() {
-	// Get provider from environment or default to openrouter
-	providerStr := strings.ToLower(strings.TrimSpace(os.Getenv(envProvider)))
-	if providerStr == "" {
-		providerStr = string(ProviderOpenRouter)
-	}
-
-	provider := Provider(providerStr)
-	if provider != ProviderOpenRouter && provider != ProviderOllama && provider != ProviderLMStudio {
-		log.Fatalf("Invalid provider: %s. Must be one of: openrouter, ollama, lmstudio", providerStr)
-	}
-
-	// Get API key from environment (required for OpenRouter, optional for others)
-	apiKey := strings.TrimSpace(os.Getenv(envAPIKey))
-	if provider == ProviderOpenRouter && apiKey == "" {
-		log.Fatalf("API key is required for OpenRouter. Set %s environment variable", envAPIKey)
-	}
-
-	// Get model from environment or use default
-	model := strings.TrimSpace(os.Getenv(envModel))
-	if model == "" {
-		if provider == ProviderOllama {
-			model = "llama2" // Default Ollama model
-		} else if provider == ProviderLMStudio {
-			model = "local-model" // Default LM Studio model
-		} else {
-			model = defaultModel
-		}
-	}
-
-	// Get provider-specific URLs
-	ollamaURL := strings.TrimSpace(os.Getenv(envOllamaURL))
-	if ollamaURL == "" {
-		ollamaURL = defaultOllamaURL
-	}
-
-	lmStudioURL := strings.TrimSpace(os.Getenv(envLMStudioURL))
-	if lmStudioURL == "" {
-		lmStudioURL = defaultLMStudioURL
-	}
-
-	// Check if streaming is enabled
-	stream := isEnvSet(envStream)
-
-	// Check if verbose mode is enabled
-	verbose := isEnvSet(envVerbose)
-
-	if verbose {
-		log.Printf("[DEBUG] Starting LLM CLI")
-		log.Printf("[DEBUG] Provider: %s", provider)
-		log.Printf("[DEBUG] Model: %s", model)
-		log.Printf("[DEBUG] Streaming: %v", stream)
-		if provider == ProviderOllama {
-			log.Printf("[DEBUG] Ollama URL: %s", ollamaURL)
-		}
-		if provider == ProviderLMStudio {
-			log.Printf("[DEBUG] LM Studio URL: %s", lmStudioURL)
-		}
-	}
-
-	// Read and prepare input from stdin
-	input, err := prepareInput(verbose)
-	if err != nil {
-		log.Fatalf("Failed to prepare input: %v", err)
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Input length: %d characters", len(input))
-	}
-
-	// Send request based on provider
-	if stream {
-		err = sendStreamingRequest(provider, apiKey, input, model, ollamaURL, lmStudioURL, verbose)
-	} else {
-		response, err := sendRequest(provider, apiKey, input, model, ollamaURL, lmStudioURL, verbose)
-		if err != nil {
-			log.Fatalf("Request failed: %v", err)
-		}
-		fmt.Println(response)
-	}
-
-	if err != nil {
-		log.Fatalf("Request failed: %v", err)
-	}
-}
-
-// isEnvSet checks if an environment variable is set to a truthy value
-func isEnvSet(key string) bool {
-	val := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
-	return val == "1" || val == "true" || val == "yes" || val == "on"
-}
-
-// prepareInput reads from stdin and optionally prepends a pre-prompt
-func prepareInput(verbose bool) (string, error) {
-	if verbose {
-		log.Printf("[DEBUG] Reading input from stdin...")
-	}
-
-	data, err := io.ReadAll(os.Stdin)
-	if err != nil {
-		return "", fmt.Errorf("failed to read from stdin: %w", err)
-	}
-
-	input := strings.TrimSpace(string(data))
-	if input == "" {
-		return "", fmt.Errorf("input is empty")
-	}
-
-	// Prepend pre-prompt if set
-	if prePrompt := os.Getenv(envPrePrompt); prePrompt != "" {
-		if verbose {
-			log.Printf("[DEBUG] Prepending pre-prompt (length: %d)", len(prePrompt))
-		}
-		input = prePrompt + "\n\n" + input
-	}
-
-	return input, nil
-}
-
-// getAPIURL returns the appropriate API URL based on provider
-func getAPIURL(provider Provider, ollamaURL, lmStudioURL string) string {
-	switch provider {
-	case ProviderOllama:
-		return ollamaURL
-	case ProviderLMStudio:
-		return lmStudioURL
-	default:
-		return openRouterURL
-	}
-}
-
-// sendRequest sends a request to the LLM API (non-streaming)
-func sendRequest(provider Provider, apiKey, input, modelName, ollamaURL, lmStudioURL string, verbose bool) (string, error) {
-	apiURL := getAPIURL(provider, ollamaURL, lmStudioURL)
-
-	// Ollama uses a slightly different request format
-	var reqBody interface{}
-	if provider == ProviderOllama {
-		reqBody = map[string]interface{}{
-			"model": modelName,
-			"messages": []ChatMessage{
-				{
-					Role:    "user",
-					Content: input,
-				},
-			},
-			"stream": false,
-		}
-	} else {
-		reqBody = OpenRouterRequest{
-			Model: modelName,
-			Messages: []ChatMessage{
-				{
-					Role:    "user",
-					Content: input,
-				},
-			},
-			Stream: false,
-		}
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Request URL: %s", apiURL)
-		log.Printf("[DEBUG] Request body size: %d bytes", len(jsonData))
-		log.Printf("[DEBUG] Request model: %s", modelName)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", userAgent)
-
-	// Only set Authorization header for OpenRouter
-	if provider == ProviderOpenRouter && apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+apiKey)
-		req.Header.Set("Referer", "https://github.com/blackopsinc/openrouter-cli")
-		req.Header.Set("X-Title", "OpenRouter CLI")
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Sending HTTP POST request...")
-	}
-
-	client := &http.Client{Timeout: defaultTimeout}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return "", fmt.Errorf("request timed out after %v", defaultTimeout)
-		}
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if verbose {
-		log.Printf("[DEBUG] Response status: %d %s", resp.StatusCode, resp.Status)
-		log.Printf("[DEBUG] Response headers: %v", resp.Header)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Response body size: %d bytes", len(body))
-	}
-
-	// Try to parse error response for non-200 status codes
-	if resp.StatusCode != http.StatusOK {
-		if provider == ProviderOllama {
-			var ollamaResp OllamaResponse
-			if err := json.Unmarshal(body, &ollamaResp); err == nil && ollamaResp.Error != "" {
-				return "", fmt.Errorf("HTTP %d - Ollama error: %s", resp.StatusCode, ollamaResp.Error)
-			}
-		} else {
-			var openRouterResp OpenRouterResponse
-			if err := json.Unmarshal(body, &openRouterResp); err == nil && openRouterResp.Error != nil {
-				return "", fmt.Errorf("HTTP %d - API error (%s): %s",
-					resp.StatusCode, openRouterResp.Error.Type, openRouterResp.Error.Message)
-			}
-		}
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Handle Ollama's native response format
-	if provider == ProviderOllama {
-		var ollamaResp OllamaResponse
-		if err := json.Unmarshal(body, &ollamaResp); err != nil {
-			if verbose {
-				log.Printf("[DEBUG] Failed to parse Ollama JSON response: %v", err)
-				log.Printf("[DEBUG] Response body (first 500 chars): %s", string(body[:min(500, len(body))]))
-			}
-			return "", fmt.Errorf("failed to parse Ollama response: %w", err)
-		}
-
-		if ollamaResp.Error != "" {
-			return "", fmt.Errorf("Ollama error: %s", ollamaResp.Error)
-		}
-
-		if verbose {
-			log.Printf("[DEBUG] Successfully received Ollama response")
-		}
-
-		return ollamaResp.Message.Content, nil
-	}
-
-	// Handle OpenAI-compatible response format (OpenRouter, LM Studio)
-	var openRouterResp OpenRouterResponse
-	if err := json.Unmarshal(body, &openRouterResp); err != nil {
-		if verbose {
-			log.Printf("[DEBUG] Failed to parse JSON response: %v", err)
-			log.Printf("[DEBUG] Response body (first 500 chars): %s", string(body[:min(500, len(body))]))
-		}
-		return "", fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if openRouterResp.Error != nil {
-		return "", fmt.Errorf("API error (%s): %s",
-			openRouterResp.Error.Type, openRouterResp.Error.Message)
-	}
-
-	if len(openRouterResp.Choices) == 0 {
-		return "", fmt.Errorf("no response received from the API")
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Successfully received response with %d choice(s)", len(openRouterResp.Choices))
-	}
-
-	return openRouterResp.Choices[0].Message.Content, nil
-}
-
-// sendStreamingRequest sends a streaming request to the LLM API (SSE)
-func sendStreamingRequest(provider Provider, apiKey, input, modelName, ollamaURL, lmStudioURL string, verbose bool) error {
-	apiURL := getAPIURL(provider, ollamaURL, lmStudioURL)
-
-	// Ollama uses a slightly different request format
-	var reqBody interface{}
-	if provider == ProviderOllama {
-		reqBody = map[string]interface{}{
-			"model": modelName,
-			"messages": []ChatMessage{
-				{
-					Role:    "user",
-					Content: input,
-				},
-			},
-			"stream": true,
-		}
-	} else {
-		reqBody = OpenRouterRequest{
-			Model: modelName,
-			Messages: []ChatMessage{
-				{
-					Role:    "user",
-					Content: input,
-				},
-			},
-			Stream: true,
-		}
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Streaming request URL: %s", apiURL)
-		log.Printf("[DEBUG] Request body size: %d bytes", len(jsonData))
-		log.Printf("[DEBUG] Request model: %s", modelName)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", userAgent)
-
-	// Only set Authorization header for OpenRouter
-	if provider == ProviderOpenRouter && apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+apiKey)
-		req.Header.Set("Referer", "https://github.com/blackopsinc/openrouter-cli")
-		req.Header.Set("X-Title", "OpenRouter CLI")
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Sending streaming HTTP POST request...")
-	}
-
-	client := &http.Client{Timeout: defaultTimeout}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("request timed out after %v", defaultTimeout)
-		}
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if verbose {
-		log.Printf("[DEBUG] Response status: %d %s", resp.StatusCode, resp.Status)
-		log.Printf("[DEBUG] Content-Type: %s", resp.Header.Get("Content-Type"))
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		if provider == ProviderOllama {
-			var ollamaResp OllamaResponse
-			if err := json.Unmarshal(body, &ollamaResp); err == nil && ollamaResp.Error != "" {
-				return fmt.Errorf("HTTP %d - Ollama error: %s", resp.StatusCode, ollamaResp.Error)
-			}
-		} else {
-			var openRouterResp OpenRouterResponse
-			if err := json.Unmarshal(body, &openRouterResp); err == nil && openRouterResp.Error != nil {
-				return fmt.Errorf("HTTP %d - API error (%s): %s",
-					resp.StatusCode, openRouterResp.Error.Type, openRouterResp.Error.Message)
-			}
-		}
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Handle Ollama's native streaming format (newline-delimited JSON, not SSE)
-	if provider == ProviderOllama {
-		scanner := bufio.NewScanner(resp.Body)
-		var fullContent strings.Builder
-		chunkCount := 0
-
-		for scanner.Scan() {
-			line := scanner.Text()
-			if line == "" {
-				continue
-			}
-
-			if verbose {
-				log.Printf("[DEBUG] Ollama stream line: %s", line)
-			}
-
-			var chunk OllamaResponse
-			if err := json.Unmarshal([]byte(line), &chunk); err != nil {
-				if verbose {
-					log.Printf("[DEBUG] Failed to parse Ollama chunk: %v", err)
-					log.Printf("[DEBUG] Chunk data: %s", line)
-				}
-				continue
-			}
-
-			if chunk.Error != "" {
-				return fmt.Errorf("Ollama error in stream: %s", chunk.Error)
-			}
-
-			if chunk.Message.Content != "" {
-				fmt.Print(chunk.Message.Content)
-				fullContent.WriteString(chunk.Message.Content)
-				chunkCount++
-			}
-
-			if chunk.Done {
-				if verbose {
-					log.Printf("[DEBUG] Ollama stream finished")
-				}
-				break
-			}
-		}
-
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("failed to read Ollama stream: %w", err)
-		}
-
-		if verbose {
-			log.Printf("[DEBUG] Ollama stream complete. Received %d chunks, total length: %d characters",
-				chunkCount, fullContent.Len())
-		}
-
-		fmt.Println()
-		return nil
-	}
-
-	// Parse SSE stream for OpenAI-compatible providers (OpenRouter, LM Studio)
-	scanner := bufio.NewScanner(resp.Body)
-	var fullContent strings.Builder
-	chunkCount := 0
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if verbose {
-			log.Printf("[DEBUG] SSE line: %s", line)
-		}
-
-		// Skip empty lines and non-data lines
-		if line == "" || !strings.HasPrefix(line, "data: ") {
-			continue
-		}
-
-		// Extract JSON data
-		data := strings.TrimPrefix(line, "data: ")
-
-		// Check for [DONE] marker
-		if data == "[DONE]" {
-			if verbose {
-				log.Printf("[DEBUG] Received [DONE] marker, stream complete")
-			}
-			break
-		}
-
-		// Parse JSON chunk
-		var chunk OpenRouterResponse
-		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-			if verbose {
-				log.Printf("[DEBUG] Failed to parse SSE chunk: %v", err)
-				log.Printf("[DEBUG] Chunk data: %s", data)
-			}
-			continue
-		}
-
-		// Check for errors in chunk
-		if chunk.Error != nil {
-			return fmt.Errorf("API error in stream (%s): %s",
-				chunk.Error.Type, chunk.Error.Message)
-		}
-
-		// Extract content from delta (streaming) or message (final)
-		if len(chunk.Choices) > 0 {
-			choice := chunk.Choices[0]
-			var content string
-
-			// Streaming responses use delta, final responses use message
-			if choice.Delta.Content != "" {
-				content = choice.Delta.Content
-			} else if choice.Message.Content != "" {
-				content = choice.Message.Content
-			}
-
-			if content != "" {
-				fmt.Print(content)
-				fullContent.WriteString(content)
-				chunkCount++
-			}
-
-			// Check for finish reason
-			if choice.FinishReason != "" {
-				if verbose {
-					log.Printf("[DEBUG] Stream finished with reason: %s", choice.FinishReason)
-				}
-				break
-			}
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read stream: %w", err)
-	}
-
-	if verbose {
-		log.Printf("[DEBUG] Stream complete. Received %d chunks, total length: %d characters",
-			chunkCount, fullContent.Len())
-	}
-
-	// Print newline after stream completes
-	fmt.Println()
-
-	return nil
-}
-
-// min returns the minimum of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+	_ "github.com/blackopsinc/openrouter-cli/internal/provider/anthropic"
+	_ "github.com/blackopsinc/openrouter-cli/internal/provider/azure"
+	_ "github.com/blackopsinc/openrouter-cli/internal/provider/google"
+	_ "github.com/blackopsinc/openrouter-cli/internal/provider/lmstudio"
+	_ "github.com/blackopsinc/openrouter-cli/internal/provider/ollama"
+	_ "github.com/blackopsinc/openrouter-cli/internal/provider/openai"
+	_ "github.com/blackopsinc/openrouter-cli/internal/provider/openrouter"
+)
+
+const (
+	envAPIKey          = "OPENROUTER_API_KEY"
+	envOpenAIAPIKey    = "OPENAI_API_KEY"
+	envAnthropicAPIKey = "ANTHROPIC_API_KEY"
+	envGoogleAPIKey    = "GOOGLE_API_KEY"
+	envAzureAPIKey     = "AZURE_OPENAI_API_KEY"
+	envAzureEndpoint   = "AZURE_OPENAI_ENDPOINT"
+	envAzureDeployment = "AZURE_OPENAI_DEPLOYMENT"
+	envAzureAPIVersion = "AZURE_OPENAI_API_VERSION"
+	envBaseURL         = "LLM_BASE_URL"
+	envProvider        = "LLM_PROVIDER"
+	envModel           = "LLM_MODEL"
+	envPrePrompt       = "LLM_PRE_PROMPT"
+	envStream          = "LLM_STREAM"
+	envVerbose         = "LLM_VERBOSE"
+	envOllamaURL       = "OLLAMA_URL"
+	envLMStudioURL     = "LM_STUDIO_URL"
+	envChat            = "LLM_CHAT"
+	envTools           = "LLM_TOOLS"
+
+	defaultModel = "openai/gpt-oss-20b:free"
+	userAgent    = "OpenRouter-CLI/1.0"
+
+	// Provider URLs
+	defaultOllamaURL   = "http://localhost:11434/api/chat"
+	defaultLMStudioURL = "http://10.8.0.22:1234/v1/chat/completions"
+)
+
+// Provider identifies which registered internal/provider backend to use. It's
+// a thin main-package alias over the registry's string keys so the rest of
+// this package (list-models, health checks) can switch on named constants.
+type Provider string
+
+const (
+	ProviderOpenRouter Provider = "openrouter"
+	ProviderOllama     Provider = "ollama"
+	ProviderLMStudio   Provider = "lmstudio"
+	ProviderOpenAI     Provider = "openai"
+	ProviderAnthropic  Provider = "anthropic"
+	ProviderGoogle     Provider = "google"
+	ProviderAzure      Provider = "azure"
+)
+
+func main() {
+	// "list-models" and "serve" are subcommands rather than flags, so they
+	// have to be recognized before flag.Parse() consumes os.Args.
+	if len(os.Args) > 1 && os.Args[1] == "list-models" {
+		runListModelsCommand()
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServeCommand(os.Args[2:])
+		return
+	}
+
+	chatFlag := flag.Bool("chat", false, "start an interactive multi-turn chat session")
+	sessionFlag := flag.String("session", "", "name of the session to resume/persist (used with -chat)")
+	paramsFlag := flag.String("params", "", "JSON object of sampling parameters, merged over LLM_* env vars")
+	flag.Parse()
+
+	verbose := isEnvSet(envVerbose)
+
+	p, cfg, err := resolveProvider(verbose)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	model := strings.TrimSpace(os.Getenv(envModel))
+	if model == "" {
+		model = defaultModelFor(p)
+	}
+
+	stream := isEnvSet(envStream)
+
+	if verbose {
+		log.Printf("[DEBUG] Starting LLM CLI")
+		log.Printf("[DEBUG] Provider: %s", p)
+		log.Printf("[DEBUG] Model: %s", model)
+		log.Printf("[DEBUG] Streaming: %v", stream)
+	}
+
+	// Fail fast if a local provider is unreachable, instead of only finding
+	// out after a full chat request times out.
+	if p == ProviderOllama || p == ProviderLMStudio {
+		if err := checkProviderHealth(p, cfg.BaseURL, cfg.BaseURL, verbose); err != nil {
+			log.Fatalf("%s is unreachable: %v", p, err)
+		}
+	}
+
+	params, err := loadGenerationParams(*paramsFlag, verbose)
+	if err != nil {
+		log.Fatalf("Invalid generation params: %v", err)
+	}
+	params.Model = model
+	// The builtin tools give the model shell/file/network access on this
+	// machine, so they're opt-in only: a remote or prompt-injected model must
+	// never be able to reach them just by being asked a question.
+	if isEnvSet(envTools) {
+		params.Tools = builtinTools()
+		params.RunTool = newToolRunner(verbose)
+	}
+
+	client, err := provider.NewClient(string(p), cfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	client = wrapWithRetry(string(p), client, verbose)
+
+	// Interactive multi-turn chat mode bypasses the single-shot pipe flow entirely
+	if *chatFlag || isEnvSet(envChat) {
+		if err := runChatMode(client, string(p), model, *sessionFlag, params, stream, verbose); err != nil {
+			log.Fatalf("Chat session failed: %v", err)
+		}
+		return
+	}
+
+	// Read and prepare input from stdin
+	input, err := prepareInput(verbose)
+	if err != nil {
+		log.Fatalf("Failed to prepare input: %v", err)
+	}
+
+	if verbose {
+		log.Printf("[DEBUG] Input length: %d characters", len(input))
+	}
+
+	messages := []provider.Message{{Role: "user", Content: input}}
+	ctx := context.Background()
+
+	var usage provider.Usage
+	start := time.Now()
+	if stream {
+		err = client.CreateChatCompletionStream(ctx, params, &messages, func(chunk string) error {
+			fmt.Print(chunk)
+			return nil
+		}, &usage)
+		if err == nil {
+			fmt.Println()
+		}
+	} else {
+		var response string
+		response, err = client.CreateChatCompletion(ctx, params, &messages, &usage)
+		if err == nil {
+			fmt.Println(response)
+		}
+	}
+
+	if err != nil {
+		log.Fatalf("Request failed: %v", err)
+	}
+	printUsageSummary(string(p), model, usage, time.Since(start), verbose)
+}
+
+// resolveProvider reads LLM_PROVIDER (defaulting to OpenRouter) and builds
+// its provider.Config via buildConfig, failing with a clear message if
+// required credentials are missing.
+func resolveProvider(verbose bool) (Provider, provider.Config, error) {
+	providerStr := strings.ToLower(strings.TrimSpace(os.Getenv(envProvider)))
+	if providerStr == "" {
+		providerStr = string(ProviderOpenRouter)
+	}
+	cfg, err := buildConfig(providerStr, verbose)
+	if err != nil {
+		return "", provider.Config{}, err
+	}
+	return Provider(providerStr), cfg, nil
+}
+
+// buildConfig builds the provider.Config for a named backend from that
+// provider's own environment variables. It's shared by resolveProvider (for
+// LLM_PROVIDER) and the LLM_FALLBACK chain, which names providers the same way.
+func buildConfig(providerStr string, verbose bool) (provider.Config, error) {
+	if !provider.Registered(providerStr) {
+		return provider.Config{}, fmt.Errorf("invalid provider: %s", providerStr)
+	}
+	p := Provider(providerStr)
+
+	cfg := provider.Config{Verbose: verbose}
+	switch p {
+	case ProviderOpenRouter:
+		cfg.APIKey = strings.TrimSpace(os.Getenv(envAPIKey))
+		if cfg.APIKey == "" {
+			return provider.Config{}, fmt.Errorf("API key is required for OpenRouter. Set %s environment variable", envAPIKey)
+		}
+	case ProviderOllama:
+		cfg.BaseURL = strings.TrimSpace(os.Getenv(envOllamaURL))
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = defaultOllamaURL
+		}
+	case ProviderLMStudio:
+		cfg.BaseURL = strings.TrimSpace(os.Getenv(envLMStudioURL))
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = defaultLMStudioURL
+		}
+	case ProviderOpenAI:
+		cfg.APIKey = strings.TrimSpace(os.Getenv(envOpenAIAPIKey))
+		cfg.BaseURL = strings.TrimSpace(os.Getenv(envBaseURL))
+		if cfg.APIKey == "" {
+			return provider.Config{}, fmt.Errorf("API key is required for OpenAI. Set %s environment variable", envOpenAIAPIKey)
+		}
+	case ProviderAnthropic:
+		cfg.APIKey = strings.TrimSpace(os.Getenv(envAnthropicAPIKey))
+		cfg.BaseURL = strings.TrimSpace(os.Getenv(envBaseURL))
+		if cfg.APIKey == "" {
+			return provider.Config{}, fmt.Errorf("API key is required for Anthropic. Set %s environment variable", envAnthropicAPIKey)
+		}
+	case ProviderGoogle:
+		cfg.APIKey = strings.TrimSpace(os.Getenv(envGoogleAPIKey))
+		cfg.BaseURL = strings.TrimSpace(os.Getenv(envBaseURL))
+		if cfg.APIKey == "" {
+			return provider.Config{}, fmt.Errorf("API key is required for Google. Set %s environment variable", envGoogleAPIKey)
+		}
+	case ProviderAzure:
+		cfg.APIKey = strings.TrimSpace(os.Getenv(envAzureAPIKey))
+		cfg.BaseURL = strings.TrimSpace(os.Getenv(envAzureEndpoint))
+		cfg.Deployment = strings.TrimSpace(os.Getenv(envAzureDeployment))
+		cfg.APIVersion = strings.TrimSpace(os.Getenv(envAzureAPIVersion))
+		if cfg.APIKey == "" || cfg.BaseURL == "" || cfg.Deployment == "" {
+			return provider.Config{}, fmt.Errorf("Azure OpenAI requires %s, %s, and %s environment variables", envAzureAPIKey, envAzureEndpoint, envAzureDeployment)
+		}
+	}
+	return cfg, nil
+}
+
+// defaultModelFor returns a provider's default model (or deployment
+// placeholder) when LLM_MODEL isn't set.
+func defaultModelFor(p Provider) string {
+	switch p {
+	case ProviderOllama:
+		return "llama2"
+	case ProviderLMStudio:
+		return "local-model"
+	case ProviderOpenAI:
+		return "gpt-4o-mini"
+	case ProviderAnthropic:
+		return "claude-3-5-sonnet-latest"
+	case ProviderGoogle:
+		return "gemini-1.5-flash"
+	case ProviderAzure:
+		// Azure addresses a deployment name, not a model name; the deployment
+		// is already bound to a specific model when it's created.
+		return "default"
+	default:
+		return defaultModel
+	}
+}
+
+// isEnvSet checks if an environment variable is set to a truthy value
+func isEnvSet(key string) bool {
+	val := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	return val == "1" || val == "true" || val == "yes" || val == "on"
+}
+
+// prepareInput reads from stdin and optionally prepends a pre-prompt
+func prepareInput(verbose bool) (string, error) {
+	if verbose {
+		log.Printf("[DEBUG] Reading input from stdin...")
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	}
+
+	input := strings.TrimSpace(string(data))
+	if input == "" {
+		return "", fmt.Errorf("input is empty")
+	}
+
+	// Prepend pre-prompt if set
+	if prePrompt := os.Getenv(envPrePrompt); prePrompt != "" {
+		if verbose {
+			log.Printf("[DEBUG] Prepending pre-prompt (length: %d)", len(prePrompt))
+		}
+		input = prePrompt + "\n\n" + input
+	}
+
+	return input, nil
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	openRouterModelsURL = "https://openrouter.ai/api/v1/models"
+
+	modelsRequestTimeout = 15 * time.Second
+)
+
+// ModelInfo is the unified row printed by `list-models`, regardless of which
+// provider it came from.
+type ModelInfo struct {
+	Provider        Provider
+	ID              string
+	ContextLength   int
+	PromptPrice     string
+	CompletionPrice string
+}
+
+// openRouterModelsResponse mirrors the relevant subset of
+// GET https://openrouter.ai/api/v1/models.
+type openRouterModelsResponse struct {
+	Data []struct {
+		ID            string `json:"id"`
+		ContextLength int    `json:"context_length"`
+		Pricing       struct {
+			Prompt     string `json:"prompt"`
+			Completion string `json:"completion"`
+		} `json:"pricing"`
+	} `json:"data"`
+}
+
+// ollamaTagsResponse mirrors Ollama's GET /api/tags.
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name    string `json:"name"`
+		Details struct {
+			ContextLength int `json:"context_length"`
+		} `json:"details"`
+	} `json:"models"`
+}
+
+// lmStudioModelsResponse mirrors LM Studio's OpenAI-compatible GET /v1/models.
+type lmStudioModelsResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// runListModelsCommand implements the `list-models` subcommand: it reads the
+// same environment variables main() does for provider URLs/credentials, then
+// prints the unified catalog table.
+func runListModelsCommand() {
+	apiKey := strings.TrimSpace(os.Getenv(envAPIKey))
+	verbose := isEnvSet(envVerbose)
+
+	ollamaURL := strings.TrimSpace(os.Getenv(envOllamaURL))
+	if ollamaURL == "" {
+		ollamaURL = defaultOllamaURL
+	}
+	lmStudioURL := strings.TrimSpace(os.Getenv(envLMStudioURL))
+	if lmStudioURL == "" {
+		lmStudioURL = defaultLMStudioURL
+	}
+
+	if err := runListModels(apiKey, ollamaURL, lmStudioURL, verbose); err != nil {
+		log.Fatalf("list-models failed: %v", err)
+	}
+}
+
+// checkProviderHealth confirms a local provider's catalog endpoint is
+// reachable before a chat request is attempted, so a down server fails fast
+// with a clear message rather than after the chat request's full timeout.
+func checkProviderHealth(provider Provider, ollamaURL, lmStudioURL string, verbose bool) error {
+	switch provider {
+	case ProviderOllama:
+		_, err := fetchOllamaModels(ollamaURL, verbose)
+		return err
+	case ProviderLMStudio:
+		_, err := fetchLMStudioModels(lmStudioURL, verbose)
+		return err
+	default:
+		return nil
+	}
+}
+
+// runListModels fetches each configured provider's model catalog and prints a
+// unified table. A provider that's unreachable here is reported as a warning
+// and skipped; it's an error only if no provider returns any models. (The
+// startup health check is checkProviderHealth, which uses the same fetchers.)
+func runListModels(apiKey, ollamaURL, lmStudioURL string, verbose bool) error {
+	var all []ModelInfo
+
+	if apiKey != "" {
+		models, err := fetchOpenRouterModels(apiKey, verbose)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to list OpenRouter models: %v\n", err)
+		} else {
+			all = append(all, models...)
+		}
+	}
+
+	if models, err := fetchOllamaModels(ollamaURL, verbose); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to list Ollama models (%s): %v\n", ollamaURL, err)
+	} else {
+		all = append(all, models...)
+	}
+
+	if models, err := fetchLMStudioModels(lmStudioURL, verbose); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to list LM Studio models (%s): %v\n", lmStudioURL, err)
+	} else {
+		all = append(all, models...)
+	}
+
+	if len(all) == 0 {
+		return fmt.Errorf("no models could be listed from any provider")
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Provider != all[j].Provider {
+			return all[i].Provider < all[j].Provider
+		}
+		return all[i].ID < all[j].ID
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tMODEL\tCONTEXT\tPROMPT $/TOKEN\tCOMPLETION $/TOKEN")
+	for _, m := range all {
+		ctxLen := "-"
+		if m.ContextLength > 0 {
+			ctxLen = fmt.Sprintf("%d", m.ContextLength)
+		}
+		prompt, completion := "-", "-"
+		if m.PromptPrice != "" {
+			prompt = m.PromptPrice
+		}
+		if m.CompletionPrice != "" {
+			completion = m.CompletionPrice
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Provider, m.ID, ctxLen, prompt, completion)
+	}
+	return w.Flush()
+}
+
+// fetchOpenRouterModels hits OpenRouter's model catalog endpoint.
+func fetchOpenRouterModels(apiKey string, verbose bool) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(context.Background(), "GET", openRouterModelsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("User-Agent", userAgent)
+
+	body, err := doModelsRequest(req, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed openRouterModelsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(parsed.Data))
+	for _, d := range parsed.Data {
+		models = append(models, ModelInfo{
+			Provider:        ProviderOpenRouter,
+			ID:              d.ID,
+			ContextLength:   d.ContextLength,
+			PromptPrice:     d.Pricing.Prompt,
+			CompletionPrice: d.Pricing.Completion,
+		})
+	}
+	return models, nil
+}
+
+// fetchOllamaModels hits Ollama's local model list (also serves as the
+// provider's authentication/health check, since Ollama has no auth).
+func fetchOllamaModels(ollamaURL string, verbose bool) ([]ModelInfo, error) {
+	tagsURL := strings.TrimSuffix(baseURL(ollamaURL), "/") + "/api/tags"
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", tagsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	body, err := doModelsRequest(req, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed ollamaTagsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(parsed.Models))
+	for _, m := range parsed.Models {
+		models = append(models, ModelInfo{
+			Provider:      ProviderOllama,
+			ID:            m.Name,
+			ContextLength: m.Details.ContextLength,
+		})
+	}
+	return models, nil
+}
+
+// fetchLMStudioModels hits LM Studio's OpenAI-compatible model list.
+func fetchLMStudioModels(lmStudioURL string, verbose bool) ([]ModelInfo, error) {
+	modelsURL := strings.TrimSuffix(baseURL(lmStudioURL), "/") + "/v1/models"
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", modelsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	body, err := doModelsRequest(req, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed lmStudioModelsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(parsed.Data))
+	for _, d := range parsed.Data {
+		models = append(models, ModelInfo{Provider: ProviderLMStudio, ID: d.ID})
+	}
+	return models, nil
+}
+
+// doModelsRequest sends a models-catalog GET request with a short timeout, so
+// an unreachable local server fails fast rather than after a full chat
+// request's timeout.
+func doModelsRequest(req *http.Request, verbose bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), modelsRequestTimeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	if verbose {
+		log.Printf("[DEBUG] Fetching model list from %s", req.URL)
+	}
+
+	client := &http.Client{Timeout: modelsRequestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("request timed out after %v (is the server reachable?)", modelsRequestTimeout)
+		}
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// baseURL strips a known chat-completions suffix from a configured provider
+// URL so the catalog endpoint can be derived from it (e.g.
+// http://host:11434/api/chat -> http://host:11434).
+func baseURL(u string) string {
+	for _, suffix := range []string{"/api/chat", "/v1/chat/completions"} {
+		if strings.HasSuffix(u, suffix) {
+			return strings.TrimSuffix(u, suffix)
+		}
+	}
+	return u
+}
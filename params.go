@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const (
+	envTemperature      = "LLM_TEMPERATURE"
+	envTopP             = "LLM_TOP_P"
+	envTopK             = "LLM_TOP_K"
+	envMaxTokens        = "LLM_MAX_TOKENS"
+	envPresencePenalty  = "LLM_PRESENCE_PENALTY"
+	envFrequencyPenalty = "LLM_FREQUENCY_PENALTY"
+	envStop             = "LLM_STOP"
+	envSeed             = "LLM_SEED"
+	envResponseFormat   = "LLM_RESPONSE_FORMAT"
+	envOllamaOptions    = "LLM_OLLAMA_OPTIONS"
+)
+
+// paramsOverrides is the subset of provider.Params that can be set via the
+// --params JSON flag; its field names mirror the OpenAI-compatible request
+// body. response_format is the bare type string (e.g. "json_object"), not
+// the API's {"type": ...} object.
+type paramsOverrides struct {
+	Temperature      *float64 `json:"temperature"`
+	TopP             *float64 `json:"top_p"`
+	TopK             *int     `json:"top_k"`
+	MaxTokens        *int     `json:"max_tokens"`
+	PresencePenalty  *float64 `json:"presence_penalty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty"`
+	Stop             []string `json:"stop"`
+	Seed             *int     `json:"seed"`
+	ResponseFormat   string   `json:"response_format"`
+}
+
+// loadGenerationParams builds a provider.Params from individual LLM_* env
+// vars, then applies paramsJSON (the --params flag contents, if any) on top
+// so flag values win over env vars field-by-field. Model, Tools, and RunTool
+// are left for the caller to fill in.
+func loadGenerationParams(paramsJSON string, verbose bool) (provider.Params, error) {
+	var p provider.Params
+
+	if v, ok := envFloat(envTemperature); ok {
+		p.Temperature = &v
+	}
+	if v, ok := envFloat(envTopP); ok {
+		p.TopP = &v
+	}
+	if v, ok := envInt(envTopK); ok {
+		p.TopK = &v
+	}
+	if v, ok := envInt(envMaxTokens); ok {
+		p.MaxTokens = &v
+	}
+	if v, ok := envFloat(envPresencePenalty); ok {
+		p.PresencePenalty = &v
+	}
+	if v, ok := envFloat(envFrequencyPenalty); ok {
+		p.FrequencyPenalty = &v
+	}
+	if raw := strings.TrimSpace(os.Getenv(envStop)); raw != "" {
+		p.Stop = strings.Split(raw, ",")
+	}
+	if v, ok := envInt(envSeed); ok {
+		p.Seed = &v
+	}
+	if raw := strings.TrimSpace(os.Getenv(envResponseFormat)); raw != "" {
+		p.ResponseFormat = raw
+	}
+	if raw := strings.TrimSpace(os.Getenv(envOllamaOptions)); raw != "" {
+		var opts map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &opts); err != nil {
+			return p, fmt.Errorf("failed to parse %s as JSON: %w", envOllamaOptions, err)
+		}
+		p.Extra = opts
+	}
+
+	if paramsJSON != "" {
+		var overrides paramsOverrides
+		if err := json.Unmarshal([]byte(paramsJSON), &overrides); err != nil {
+			return p, fmt.Errorf("failed to parse --params JSON: %w", err)
+		}
+		applyOverrides(&p, overrides)
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Generation params: %+v\n", p)
+	}
+
+	return p, nil
+}
+
+func applyOverrides(p *provider.Params, o paramsOverrides) {
+	if o.Temperature != nil {
+		p.Temperature = o.Temperature
+	}
+	if o.TopP != nil {
+		p.TopP = o.TopP
+	}
+	if o.TopK != nil {
+		p.TopK = o.TopK
+	}
+	if o.MaxTokens != nil {
+		p.MaxTokens = o.MaxTokens
+	}
+	if o.PresencePenalty != nil {
+		p.PresencePenalty = o.PresencePenalty
+	}
+	if o.FrequencyPenalty != nil {
+		p.FrequencyPenalty = o.FrequencyPenalty
+	}
+	if o.Stop != nil {
+		p.Stop = o.Stop
+	}
+	if o.Seed != nil {
+		p.Seed = o.Seed
+	}
+	if o.ResponseFormat != "" {
+		p.ResponseFormat = o.ResponseFormat
+	}
+}
+
+func envFloat(key string) (float64, bool) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+func envInt(key string) (int, bool) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return 0, false
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const (
+	envMaxRetries  = "LLM_MAX_RETRIES"
+	envRetryBudget = "LLM_RETRY_BUDGET"
+	envFallback    = "LLM_FALLBACK"
+
+	defaultMaxRetries   = 3
+	defaultRetryBudgetS = 120 // seconds
+	baseRetryDelay      = 500 * time.Millisecond
+	maxRetryDelay       = 30 * time.Second
+)
+
+// fallbackTarget is one entry of LLM_FALLBACK: a ready-to-use client for a
+// provider, plus the model to request from it.
+type fallbackTarget struct {
+	providerName string
+	model        string
+	client       provider.ChatCompletionClient
+}
+
+// retryingClient wraps a provider.ChatCompletionClient with exponential
+// backoff on transient errors and, once that's exhausted, a fallback chain
+// of other provider:model pairs tried in order.
+type retryingClient struct {
+	name        string
+	client      provider.ChatCompletionClient
+	fallbacks   []fallbackTarget
+	maxRetries  int
+	retryBudget time.Duration
+	verbose     bool
+}
+
+// wrapWithRetry wraps client (registered under name) with retry/backoff
+// (LLM_MAX_RETRIES, LLM_RETRY_BUDGET) and the LLM_FALLBACK chain, if configured.
+func wrapWithRetry(name string, client provider.ChatCompletionClient, verbose bool) provider.ChatCompletionClient {
+	return &retryingClient{
+		name:        name,
+		client:      client,
+		fallbacks:   loadFallbacks(verbose),
+		maxRetries:  envIntDefault(envMaxRetries, defaultMaxRetries),
+		retryBudget: time.Duration(envIntDefault(envRetryBudget, defaultRetryBudgetS)) * time.Second,
+		verbose:     verbose,
+	}
+}
+
+// CreateChatCompletion implements provider.ChatCompletionClient. messages is
+// reset to its pre-call contents before every attempt (initial, retry, or
+// fallback): a tool-calling client appends assistant/tool turns to *messages
+// as it goes, so a round that fails partway through a multi-round tool loop
+// would otherwise leave the next attempt starting from an already-mutated,
+// partially-duplicated history instead of a clean one.
+func (r *retryingClient) CreateChatCompletion(ctx context.Context, params provider.Params, messages *[]provider.Message, usage *provider.Usage) (string, error) {
+	snapshot := append([]provider.Message(nil), (*messages)...)
+	content, err := withRetry(r.name, r.maxRetries, r.retryBudget, r.verbose, func() (string, error) {
+		*messages = append([]provider.Message(nil), snapshot...)
+		return r.client.CreateChatCompletion(ctx, params, messages, usage)
+	})
+	if err == nil {
+		return content, nil
+	}
+
+	for _, fb := range r.fallbacks {
+		if r.verbose {
+			log.Printf("[DEBUG] %s exhausted (%v), falling back to %s:%s", r.name, err, fb.providerName, fb.model)
+		}
+		fbParams := params
+		fbParams.Model = fb.model
+		content, fbErr := withRetry(fb.providerName, r.maxRetries, r.retryBudget, r.verbose, func() (string, error) {
+			*messages = append([]provider.Message(nil), snapshot...)
+			return fb.client.CreateChatCompletion(ctx, fbParams, messages, usage)
+		})
+		if fbErr == nil {
+			return content, nil
+		}
+		err = fbErr
+	}
+	*messages = append([]provider.Message(nil), snapshot...)
+	return "", err
+}
+
+// CreateChatCompletionStream implements provider.ChatCompletionClient. Per
+// LLM_FALLBACK semantics, a fallback is only attempted if chunk was never
+// called for a given target, so a partially-streamed reply is never followed
+// by a second, unrelated one. messages is reset to its pre-call contents
+// before every attempt for the same reason as CreateChatCompletion: a tool
+// loop mutates it in place, and a failed round must not leave the next
+// attempt starting from that partial mutation.
+func (r *retryingClient) CreateChatCompletionStream(ctx context.Context, params provider.Params, messages *[]provider.Message, chunk func(string) error, usage *provider.Usage) error {
+	snapshot := append([]provider.Message(nil), (*messages)...)
+	emitted := false
+	tracked := func(s string) error {
+		emitted = true
+		return chunk(s)
+	}
+
+	err := withRetryErr(r.name, r.maxRetries, r.retryBudget, r.verbose, func() error {
+		*messages = append([]provider.Message(nil), snapshot...)
+		return r.client.CreateChatCompletionStream(ctx, params, messages, tracked, usage)
+	})
+	if err == nil || emitted {
+		return err
+	}
+
+	for _, fb := range r.fallbacks {
+		if r.verbose {
+			log.Printf("[DEBUG] %s exhausted (%v), falling back to %s:%s", r.name, err, fb.providerName, fb.model)
+		}
+		fbParams := params
+		fbParams.Model = fb.model
+		emitted = false
+		fbErr := withRetryErr(fb.providerName, r.maxRetries, r.retryBudget, r.verbose, func() error {
+			*messages = append([]provider.Message(nil), snapshot...)
+			return fb.client.CreateChatCompletionStream(ctx, fbParams, messages, tracked, usage)
+		})
+		if fbErr == nil {
+			return nil
+		}
+		if emitted {
+			return fbErr
+		}
+		err = fbErr
+	}
+	*messages = append([]provider.Message(nil), snapshot...)
+	return err
+}
+
+// withRetry invokes fn up to maxRetries+1 times, backing off between
+// attempts for retryable errors (429/5xx/connection failures) and stopping
+// early once a non-retryable error is returned or retryBudget has elapsed.
+func withRetry(label string, maxRetries int, retryBudget time.Duration, verbose bool, fn func() (string, error)) (string, error) {
+	start := time.Now()
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		content, err := fn()
+		if err == nil {
+			return content, nil
+		}
+		lastErr = err
+		if !provider.Retryable(err) || attempt == maxRetries {
+			break
+		}
+
+		wait := backoffDelay(attempt, err)
+		if retryBudget > 0 && time.Since(start)+wait > retryBudget {
+			if verbose {
+				log.Printf("[DEBUG] %s: retry budget exhausted after %d attempt(s)", label, attempt+1)
+			}
+			break
+		}
+		if verbose {
+			log.Printf("[DEBUG] %s: attempt %d failed (%v), retrying in %v", label, attempt+1, err, wait)
+		}
+		time.Sleep(wait)
+	}
+	return "", lastErr
+}
+
+// withRetryErr is withRetry for calls with no content to return, such as a
+// streaming round trip whose result is delivered via callback.
+func withRetryErr(label string, maxRetries int, retryBudget time.Duration, verbose bool, fn func() error) error {
+	_, err := withRetry(label, maxRetries, retryBudget, verbose, func() (string, error) {
+		return "", fn()
+	})
+	return err
+}
+
+// backoffDelay computes the wait before the next attempt: the server's
+// Retry-After when present, otherwise exponential backoff from
+// baseRetryDelay with up to 50% jitter, capped at maxRetryDelay.
+func backoffDelay(attempt int, err error) time.Duration {
+	var httpErr *provider.HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		return httpErr.RetryAfter
+	}
+
+	delay := baseRetryDelay << attempt
+	if delay > maxRetryDelay || delay <= 0 {
+		delay = maxRetryDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
+}
+
+// loadFallbacks parses LLM_FALLBACK ("provider:model,provider:model,...")
+// into ready-to-use clients, skipping (with a warning) any entry that names
+// an unknown provider or one missing its required credentials.
+func loadFallbacks(verbose bool) []fallbackTarget {
+	raw := strings.TrimSpace(os.Getenv(envFallback))
+	if raw == "" {
+		return nil
+	}
+
+	var targets []fallbackTarget
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			fmt.Fprintf(os.Stderr, "warning: ignoring malformed %s entry %q (want provider:model)\n", envFallback, entry)
+			continue
+		}
+		name, model := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+		cfg, err := buildConfig(name, verbose)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping fallback %q: %v\n", entry, err)
+			continue
+		}
+		client, err := provider.NewClient(name, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping fallback %q: %v\n", entry, err)
+			continue
+		}
+		targets = append(targets, fallbackTarget{providerName: name, model: model, client: client})
+	}
+	return targets
+}
+
+// envIntDefault parses key as an integer, falling back to def if unset or invalid.
+func envIntDefault(key string, def int) int {
+	if v, ok := envInt(key); ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+func TestBackoffDelayRespectsRetryAfter(t *testing.T) {
+	err := &provider.HTTPError{StatusCode: 429, RetryAfter: 7 * time.Second}
+
+	if got := backoffDelay(3, err); got != 7*time.Second {
+		t.Errorf("expected the server's Retry-After to win, got %v", got)
+	}
+}
+
+func TestBackoffDelayCapsAtMaxRetryDelay(t *testing.T) {
+	err := errors.New("boom")
+
+	// A large attempt number would overflow baseRetryDelay<<attempt well past
+	// maxRetryDelay; the base delay is capped at maxRetryDelay before up to
+	// 50% jitter is added on top, so the result must never exceed 1.5x that cap.
+	got := backoffDelay(20, err)
+	if got > maxRetryDelay+maxRetryDelay/2 {
+		t.Errorf("expected delay to be capped around %v, got %v", maxRetryDelay, got)
+	}
+	if got <= 0 {
+		t.Errorf("expected a positive delay, got %v", got)
+	}
+}
+
+func TestBackoffDelayGrowsWithAttempt(t *testing.T) {
+	err := errors.New("boom")
+
+	// Jitter makes an exact comparison flaky, but the base (pre-jitter) delay
+	// for attempt 4 (8s) already exceeds attempt 0's largest jittered delay
+	// (500ms plus up to 250ms).
+	small := backoffDelay(0, err)
+	large := backoffDelay(4, err)
+	if large <= small {
+		t.Errorf("expected backoff to grow with attempt number, got attempt 0 = %v, attempt 4 = %v", small, large)
+	}
+}
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+	if got := provider.ParseRetryAfter("5"); got != 5*time.Second {
+		t.Errorf("expected 5s, got %v", got)
+	}
+}
+
+func TestParseRetryAfterEmptyOrInvalid(t *testing.T) {
+	if got := provider.ParseRetryAfter(""); got != 0 {
+		t.Errorf("expected 0 for an empty header, got %v", got)
+	}
+	if got := provider.ParseRetryAfter("not-a-valid-value"); got != 0 {
+		t.Errorf("expected 0 for an unparseable header, got %v", got)
+	}
+	if got := provider.ParseRetryAfter("-5"); got != 0 {
+		t.Errorf("expected 0 for a negative seconds value, got %v", got)
+	}
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	got := provider.ParseRetryAfter(future)
+	if got <= 0 || got > 30*time.Second {
+		t.Errorf("expected a positive delay near 30s, got %v", got)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+// server holds the state shared by the serve mode's HTTP handlers: the
+// backend client (already wrapped with retry/fallback) and the provider's
+// connection config, reused to serve GET /v1/models.
+type server struct {
+	providerName Provider
+	cfg          provider.Config
+	client       provider.ChatCompletionClient
+	verbose      bool
+}
+
+// chatCompletionRequest mirrors the subset of the OpenAI chat/completions
+// request body this server accepts.
+type chatCompletionRequest struct {
+	Model            string             `json:"model"`
+	Messages         []provider.Message `json:"messages"`
+	Stream           bool               `json:"stream,omitempty"`
+	Temperature      *float64           `json:"temperature,omitempty"`
+	TopP             *float64           `json:"top_p,omitempty"`
+	MaxTokens        *int               `json:"max_tokens,omitempty"`
+	PresencePenalty  *float64           `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64           `json:"frequency_penalty,omitempty"`
+	Stop             []string           `json:"stop,omitempty"`
+	Seed             *int               `json:"seed,omitempty"`
+	Tools            []wireToolDef      `json:"tools,omitempty"`
+	ToolChoice       interface{}        `json:"tool_choice,omitempty"`
+}
+
+// wireToolDef mirrors the OpenAI tools request format. serve never executes
+// tool calls itself (see handleChatCompletions); it only forwards whatever
+// tool definitions the caller supplied on to the upstream model.
+type wireToolDef struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Parameters  json.RawMessage `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+func toProviderTools(tools []wireToolDef) []provider.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]provider.Tool, len(tools))
+	for i, t := range tools {
+		out[i] = provider.Tool{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			Parameters:  t.Function.Parameters,
+		}
+	}
+	return out
+}
+
+// chatCompletionResponse mirrors both the non-streaming OpenAI
+// chat/completions response and its streaming chat.completion.chunk variant;
+// a chunk populates Delta instead of Message.
+type chatCompletionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []chatCompletionChoice `json:"choices"`
+	Usage   *chatCompletionUsage   `json:"usage,omitempty"`
+}
+
+type chatCompletionUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type chatCompletionChoice struct {
+	Index        int                  `json:"index"`
+	Message      *provider.Message    `json:"message,omitempty"`
+	Delta        *chatCompletionDelta `json:"delta,omitempty"`
+	FinishReason string               `json:"finish_reason,omitempty"`
+}
+
+type chatCompletionDelta struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// runServeCommand implements the `serve` subcommand: an OpenAI-compatible
+// local HTTP server that forwards chat completions to whichever backend
+// LLM_PROVIDER selects, reusing the same ChatCompletionClient (and its
+// retry/fallback wrapping) as the one-shot and chat modes.
+func runServeCommand(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	fs.Parse(args)
+
+	verbose := isEnvSet(envVerbose)
+
+	p, cfg, err := resolveProvider(verbose)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	client, err := provider.NewClient(string(p), cfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	client = wrapWithRetry(string(p), client, verbose)
+
+	srv := &server{providerName: p, cfg: cfg, client: client, verbose: verbose}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", srv.handleChatCompletions)
+	mux.HandleFunc("/v1/models", srv.handleModels)
+
+	log.Printf("Listening on %s (provider: %s)", *addr, p)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf("serve: %v", err)
+	}
+}
+
+// handleChatCompletions implements POST /v1/chat/completions, both streaming
+// (SSE) and non-streaming, over whichever backend was selected at startup.
+func (s *server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req chatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if len(req.Messages) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "messages must not be empty")
+		return
+	}
+
+	// serve is a network-reachable HTTP proxy, so unlike the local CLI/chat
+	// modes it never wires up the builtin shell/file/network tools -
+	// whoever can reach this port must not be able to get the upstream
+	// model to execute anything on this host. A caller's own tool
+	// definitions are forwarded as-is; if the model calls one, there's no
+	// RunTool to run it and the request fails with a clear error instead of
+	// silently executing a builtin in its place.
+	params := provider.Params{
+		Model:            req.Model,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		MaxTokens:        req.MaxTokens,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+		Stop:             req.Stop,
+		Seed:             req.Seed,
+		Tools:            toProviderTools(req.Tools),
+		ToolChoice:       req.ToolChoice,
+	}
+	messages := req.Messages
+	id := completionID()
+	created := time.Now().Unix()
+
+	if s.verbose {
+		log.Printf("[DEBUG] %s %s model=%s stream=%v messages=%d", r.Method, r.URL.Path, params.Model, req.Stream, len(messages))
+	}
+
+	if req.Stream {
+		s.streamChatCompletion(w, r, params, &messages, id, created)
+		return
+	}
+
+	start := time.Now()
+	var usage provider.Usage
+	content, err := s.client.CreateChatCompletion(r.Context(), params, &messages, &usage)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	printUsageSummary(string(s.providerName), params.Model, usage, time.Since(start), s.verbose)
+
+	resp := chatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   params.Model,
+		Choices: []chatCompletionChoice{{
+			Index:        0,
+			Message:      &provider.Message{Role: "assistant", Content: content},
+			FinishReason: "stop",
+		}},
+		Usage: &chatCompletionUsage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil && s.verbose {
+		log.Printf("[DEBUG] failed to write response: %v", err)
+	}
+}
+
+// streamChatCompletion writes an SSE stream of chat.completion.chunk events,
+// matching OpenAI's streaming wire format, followed by a final "data: [DONE]".
+func (s *server) streamChatCompletion(w http.ResponseWriter, r *http.Request, params provider.Params, messages *[]provider.Message, id string, created int64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, "streaming not supported by this response writer")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	writeChunk := func(delta chatCompletionDelta, finishReason string) {
+		chunk := chatCompletionResponse{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   params.Model,
+			Choices: []chatCompletionChoice{{Index: 0, Delta: &delta, FinishReason: finishReason}},
+		}
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	writeChunk(chatCompletionDelta{Role: "assistant"}, "")
+
+	start := time.Now()
+	var usage provider.Usage
+	err := s.client.CreateChatCompletionStream(r.Context(), params, messages, func(content string) error {
+		writeChunk(chatCompletionDelta{Content: content}, "")
+		return nil
+	}, &usage)
+
+	finishReason := "stop"
+	if err != nil {
+		if s.verbose {
+			log.Printf("[DEBUG] stream error: %v", err)
+		}
+		finishReason = "error"
+	} else {
+		printUsageSummary(string(s.providerName), params.Model, usage, time.Since(start), s.verbose)
+	}
+	writeChunk(chatCompletionDelta{}, finishReason)
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// handleModels implements GET /v1/models, returning the backend's catalog in
+// OpenAI's list format when the provider has one wired up, or a single entry
+// for the configured model otherwise.
+func (s *server) handleModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	models, err := s.listModels()
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	data := make([]map[string]interface{}, 0, len(models))
+	for _, m := range models {
+		data = append(data, map[string]interface{}{
+			"id":       m.ID,
+			"object":   "model",
+			"owned_by": string(m.Provider),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data})
+}
+
+// listModels returns the catalog for the server's configured provider. Only
+// OpenRouter, Ollama, and LM Studio have a real catalog endpoint wired up
+// (see models.go); other providers fall back to a single entry for LLM_MODEL.
+func (s *server) listModels() ([]ModelInfo, error) {
+	switch s.providerName {
+	case ProviderOpenRouter:
+		return fetchOpenRouterModels(s.cfg.APIKey, s.verbose)
+	case ProviderOllama:
+		return fetchOllamaModels(s.cfg.BaseURL, s.verbose)
+	case ProviderLMStudio:
+		return fetchLMStudioModels(s.cfg.BaseURL, s.verbose)
+	default:
+		model := strings.TrimSpace(os.Getenv(envModel))
+		if model == "" {
+			model = defaultModelFor(s.providerName)
+		}
+		return []ModelInfo{{Provider: s.providerName, ID: model}}, nil
+	}
+}
+
+func completionID() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return "chatcmpl-" + hex.EncodeToString(buf)
+}
+
+// writeAPIError writes an OpenAI-style {"error": {...}} JSON error body.
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{"message": message},
+	})
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const (
+	envChatMaxChars = "LLM_CHAT_MAX_CHARS"
+
+	// defaultChatMaxChars caps the in-memory transcript by an approximate
+	// character count (~4 chars/token) rather than an exact token count,
+	// since trimming doesn't need to be precise, just bounded.
+	defaultChatMaxChars = 24000
+
+	sessionDirName = ".openrouter-cli/sessions"
+)
+
+// ChatSession is the on-disk/in-memory representation of a resumable chat.
+// It's persisted as plain JSON so a transcript can be inspected or edited
+// by hand between runs.
+type ChatSession struct {
+	Name     string             `json:"name"`
+	Model    string             `json:"model"`
+	Messages []provider.Message `json:"messages"`
+}
+
+// loadOrCreateSession loads a named session from disk, or returns a fresh one
+// if it doesn't exist yet. An empty name yields an unnamed, in-memory-only
+// session (nothing is persisted on save).
+func loadOrCreateSession(name, model string) (*ChatSession, error) {
+	if name == "" {
+		return &ChatSession{Model: model}, nil
+	}
+
+	path, err := sessionPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &ChatSession{Name: name, Model: model}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session %q: %w", name, err)
+	}
+
+	var session ChatSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session %q: %w", name, err)
+	}
+	session.Name = name
+	if session.Model == "" {
+		session.Model = model
+	}
+	return &session, nil
+}
+
+// Save persists the session transcript to ~/.openrouter-cli/sessions/<name>.json.
+// It's a no-op for unnamed sessions.
+func (s *ChatSession) Save() error {
+	if s.Name == "" {
+		return nil
+	}
+
+	path, err := sessionPath(s.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session %q: %w", s.Name, err)
+	}
+	return nil
+}
+
+// Reset clears the conversation history, keeping a leading system message (if any).
+func (s *ChatSession) Reset() {
+	if len(s.Messages) > 0 && s.Messages[0].Role == "system" {
+		s.Messages = s.Messages[:1]
+		return
+	}
+	s.Messages = nil
+}
+
+// trimHistory drops the oldest non-system turns once the approximate
+// character count of the transcript exceeds maxChars, so long-running
+// sessions don't grow the request payload without bound. It always leaves
+// the most-recently-appended message in place, even if that single message
+// alone exceeds maxChars, so the transcript sent to the model is never empty.
+func (s *ChatSession) trimHistory(maxChars int) {
+	total := 0
+	for _, m := range s.Messages {
+		total += len(m.Content)
+	}
+
+	start := 0
+	if len(s.Messages) > 0 && s.Messages[0].Role == "system" {
+		start = 1
+	}
+
+	for total > maxChars && start < len(s.Messages)-1 {
+		total -= len(s.Messages[start].Content)
+		s.Messages = append(s.Messages[:start], s.Messages[start+1:]...)
+	}
+}
+
+// sessionPath resolves the JSON transcript path for a named session.
+func sessionPath(name string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(homeDir, sessionDirName, name+".json"), nil
+}
+
+// chatMaxChars returns the configured history cap, falling back to the default.
+func chatMaxChars() int {
+	raw := strings.TrimSpace(os.Getenv(envChatMaxChars))
+	if raw == "" {
+		return defaultChatMaxChars
+	}
+	var n int
+	if _, err := fmt.Sscanf(raw, "%d", &n); err != nil || n <= 0 {
+		return defaultChatMaxChars
+	}
+	return n
+}
+
+// runChatMode starts an interactive REPL that maintains a rolling conversation,
+// optionally persisting it to a named session so it can be resumed later.
+// This is the multi-turn counterpart to the default single-shot pipe mode.
+func runChatMode(client provider.ChatCompletionClient, providerName, model, sessionName string, params provider.Params, stream, verbose bool) error {
+	session, err := loadOrCreateSession(sessionName, model)
+	if err != nil {
+		return err
+	}
+	if session.Model != "" {
+		model = session.Model
+	}
+
+	if prePrompt := os.Getenv(envPrePrompt); prePrompt != "" && len(session.Messages) == 0 {
+		session.Messages = append(session.Messages, provider.Message{Role: "system", Content: prePrompt})
+	}
+
+	maxChars := chatMaxChars()
+	ctx := context.Background()
+
+	fmt.Printf("Chat mode (provider: %s, model: %s). Commands: /reset /save /load <name> /model <id> /exit\n", providerName, model)
+	if sessionName != "" {
+		fmt.Printf("Session %q loaded with %d prior message(s).\n", sessionName, len(session.Messages))
+	}
+
+	reader := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("you> ")
+		if !reader.Scan() {
+			break
+		}
+		line := strings.TrimSpace(reader.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			done, err := handleChatCommand(line, session, &model)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			}
+			if done {
+				break
+			}
+			continue
+		}
+
+		session.Messages = append(session.Messages, provider.Message{Role: "user", Content: line})
+		session.trimHistory(maxChars)
+
+		params.Model = model
+
+		var reply string
+		var usage provider.Usage
+		start := time.Now()
+		if stream {
+			fmt.Print("assistant> ")
+			err = client.CreateChatCompletionStream(ctx, params, &session.Messages, func(chunk string) error {
+				fmt.Print(chunk)
+				reply += chunk
+				return nil
+			}, &usage)
+			if err == nil {
+				fmt.Println()
+			}
+		} else {
+			reply, err = client.CreateChatCompletion(ctx, params, &session.Messages, &usage)
+			if err == nil {
+				fmt.Printf("assistant> %s\n", reply)
+			}
+		}
+		if err == nil {
+			printUsageSummary(providerName, model, usage, time.Since(start), verbose)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "request failed: %v\n", err)
+			// Drop the user turn we just appended so a failed request doesn't
+			// poison the next one with an unanswered message.
+			session.Messages = session.Messages[:len(session.Messages)-1]
+			continue
+		}
+
+		session.Messages = append(session.Messages, provider.Message{Role: "assistant", Content: reply})
+		session.trimHistory(maxChars)
+
+		if session.Name != "" {
+			if err := session.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to save session: %v\n", err)
+			}
+		}
+	}
+
+	if err := reader.Err(); err != nil {
+		return fmt.Errorf("failed to read from stdin: %w", err)
+	}
+	return session.Save()
+}
+
+// handleChatCommand processes a leading-slash REPL command. The returned bool
+// reports whether the chat loop should exit.
+func handleChatCommand(line string, session *ChatSession, model *string) (bool, error) {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/exit", "/quit":
+		return true, nil
+	case "/reset":
+		session.Reset()
+		fmt.Println("history cleared")
+	case "/save":
+		if err := session.Save(); err != nil {
+			return false, err
+		}
+		fmt.Println("session saved")
+	case "/load":
+		if len(fields) < 2 {
+			return false, fmt.Errorf("usage: /load <name>")
+		}
+		loaded, err := loadOrCreateSession(fields[1], *model)
+		if err != nil {
+			return false, err
+		}
+		*session = *loaded
+		if session.Model != "" {
+			*model = session.Model
+		}
+		fmt.Printf("loaded session %q with %d message(s)\n", session.Name, len(session.Messages))
+	case "/model":
+		if len(fields) < 2 {
+			return false, fmt.Errorf("usage: /model <id>")
+		}
+		*model = fields[1]
+		session.Model = fields[1]
+		fmt.Printf("model set to %s\n", *model)
+	default:
+		return false, fmt.Errorf("unknown command: %s", fields[0])
+	}
+	return false, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+func TestTrimHistoryDropsOldestFirst(t *testing.T) {
+	s := &ChatSession{Messages: []provider.Message{
+		{Role: "system", Content: "sys"},
+		{Role: "user", Content: "aaaaaaaaaa"},
+		{Role: "assistant", Content: "bbbbbbbbbb"},
+		{Role: "user", Content: "cc"},
+	}}
+
+	s.trimHistory(1)
+
+	if len(s.Messages) != 2 {
+		t.Fatalf("expected 2 messages left, got %d: %+v", len(s.Messages), s.Messages)
+	}
+	if s.Messages[0].Role != "system" {
+		t.Errorf("expected the system message to be kept, got %q", s.Messages[0].Role)
+	}
+	if s.Messages[1].Content != "cc" {
+		t.Errorf("expected the most recent turn to survive, got %q", s.Messages[1].Content)
+	}
+}
+
+func TestTrimHistoryNeverDropsTheLastMessage(t *testing.T) {
+	s := &ChatSession{Messages: []provider.Message{
+		{Role: "system", Content: "sys"},
+		{Role: "user", Content: "a single turn longer than the cap"},
+	}}
+
+	s.trimHistory(5)
+
+	if len(s.Messages) != 2 {
+		t.Fatalf("expected the system message and the last turn to survive, got %d: %+v", len(s.Messages), s.Messages)
+	}
+	if s.Messages[1].Content != "a single turn longer than the cap" {
+		t.Errorf("expected the most recent turn to be kept untouched, got %q", s.Messages[1].Content)
+	}
+}
+
+func TestTrimHistoryNoSystemMessage(t *testing.T) {
+	s := &ChatSession{Messages: []provider.Message{
+		{Role: "user", Content: "aaaaaaaaaa"},
+		{Role: "assistant", Content: "bb"},
+	}}
+
+	s.trimHistory(5)
+
+	if len(s.Messages) != 1 {
+		t.Fatalf("expected only the last message to survive, got %d: %+v", len(s.Messages), s.Messages)
+	}
+	if s.Messages[0].Content != "bb" {
+		t.Errorf("expected the last message to be kept, got %q", s.Messages[0].Content)
+	}
+}
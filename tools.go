@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+// toolHandler executes a tool call's arguments and returns the text to feed
+// back to the model as a "tool" role message.
+type toolHandler func(args json.RawMessage) (string, error)
+
+// toolDef pairs a handler with the provider.Tool definition advertised to
+// the model.
+type toolDef struct {
+	tool    provider.Tool
+	handler toolHandler
+}
+
+// toolRegistry maps a tool name to its definition and local handler.
+// Populated by registerBuiltinTools and consulted by newToolRunner.
+var toolRegistry = map[string]toolDef{}
+
+func init() {
+	registerBuiltinTools()
+}
+
+// registerBuiltinTools wires up the local tool handlers offered to the
+// model: shell execution, file reads, and HTTP fetches.
+func registerBuiltinTools() {
+	toolRegistry["shell_exec"] = toolDef{
+		tool: provider.Tool{
+			Name:        "shell_exec",
+			Description: "Run a shell command and return its combined stdout/stderr output.",
+			Parameters:  []byte(`{"type":"object","properties":{"command":{"type":"string"}},"required":["command"]}`),
+		},
+		handler: shellExecTool,
+	}
+	toolRegistry["file_read"] = toolDef{
+		tool: provider.Tool{
+			Name:        "file_read",
+			Description: "Read the contents of a local file.",
+			Parameters:  []byte(`{"type":"object","properties":{"path":{"type":"string"}},"required":["path"]}`),
+		},
+		handler: fileReadTool,
+	}
+	toolRegistry["http_fetch"] = toolDef{
+		tool: provider.Tool{
+			Name:        "http_fetch",
+			Description: "Fetch a URL over HTTP(S) and return the response body.",
+			Parameters:  []byte(`{"type":"object","properties":{"url":{"type":"string"}},"required":["url"]}`),
+		},
+		handler: httpFetchTool,
+	}
+}
+
+// builtinTools returns the provider.Tool definitions for the registered
+// handlers, to be sent to the model as the request's tools field.
+func builtinTools() []provider.Tool {
+	tools := make([]provider.Tool, 0, len(toolRegistry))
+	for _, def := range toolRegistry {
+		tools = append(tools, def.tool)
+	}
+	return tools
+}
+
+// newToolRunner returns a provider.ToolRunner backed by the builtin tool
+// registry, for wiring into provider.Params.RunTool.
+func newToolRunner(verbose bool) provider.ToolRunner {
+	return func(call provider.ToolCall) (string, error) {
+		def, ok := toolRegistry[call.Name]
+		if !ok {
+			return "", fmt.Errorf("no local handler registered for tool %q", call.Name)
+		}
+		result, err := def.handler(json.RawMessage(call.Arguments))
+		if verbose {
+			log.Printf("[DEBUG] Tool %s -> %d bytes (err=%v)", call.Name, len(result), err)
+		}
+		return result, err
+	}
+}
+
+// confirmToolAction prints what a tool is about to do and asks the user to
+// approve it on the controlling terminal before the tool runs. shell_exec,
+// file_read, and http_fetch all act on the model's say-so - and that model may
+// be responding to a prompt-injected webpage or an adversarial upstream - so
+// none of them runs without a human in the loop: file_read can reach any file
+// the user can read (~/.ssh/id_rsa, .env, cloud credential files) and
+// http_fetch can exfiltrate it to an attacker-controlled URL right after. If
+// there's no controlling terminal to ask on, the action is refused rather
+// than run.
+func confirmToolAction(description string) (bool, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, fmt.Errorf("tool execution requires interactive confirmation but no controlling terminal is available: %w", err)
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "\n%s\nallow? [y/N] ", description)
+	line, _ := bufio.NewReader(tty).ReadString('\n')
+	return strings.EqualFold(strings.TrimSpace(line), "y"), nil
+}
+
+func shellExecTool(args json.RawMessage) (string, error) {
+	var params struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid shell_exec arguments: %w", err)
+	}
+	if strings.TrimSpace(params.Command) == "" {
+		return "", fmt.Errorf("shell_exec requires a non-empty command")
+	}
+
+	allowed, err := confirmToolAction(fmt.Sprintf("model wants to run: %s", params.Command))
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return "", fmt.Errorf("shell_exec denied: user did not confirm the command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", params.Command)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("command failed: %w", err)
+	}
+	return string(out), nil
+}
+
+func fileReadTool(args json.RawMessage) (string, error) {
+	var params struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid file_read arguments: %w", err)
+	}
+
+	allowed, err := confirmToolAction(fmt.Sprintf("model wants to read file: %s", params.Path))
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return "", fmt.Errorf("file_read denied: user did not confirm the path")
+	}
+
+	data, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %q: %w", params.Path, err)
+	}
+	return string(data), nil
+}
+
+func httpFetchTool(args json.RawMessage) (string, error) {
+	var params struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid http_fetch arguments: %w", err)
+	}
+
+	allowed, err := confirmToolAction(fmt.Sprintf("model wants to fetch URL: %s", params.URL))
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return "", fmt.Errorf("http_fetch denied: user did not confirm the URL")
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(params.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %q: %w", params.URL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	return string(body), nil
+}
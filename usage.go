@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blackopsinc/openrouter-cli/internal/provider"
+)
+
+const (
+	envUsage = "LLM_USAGE"
+
+	pricingCacheFile = ".openrouter-cli/pricing.json"
+	pricingCacheTTL  = 24 * time.Hour
+)
+
+// modelPricing is a model's dollars-per-token rates, as reported by
+// OpenRouter's /models pricing table.
+type modelPricing struct {
+	PromptPrice     float64
+	CompletionPrice float64
+}
+
+// pricingCache is the on-disk shape of ~/.openrouter-cli/pricing.json: a
+// snapshot of OpenRouter's pricing table plus when it was fetched, so it can
+// be reused across runs instead of hitting /models on every request.
+type pricingCache struct {
+	FetchedAt int64                   `json:"fetched_at"`
+	Models    map[string]modelPricing `json:"models"`
+}
+
+// printUsageSummary writes a single usage/cost line to stderr, gated by
+// LLM_USAGE=1. tokens/sec prefers Ollama's native eval duration when present,
+// falling back to the wall-clock time the caller measured around the request.
+func printUsageSummary(providerName, model string, usage provider.Usage, elapsed time.Duration, verbose bool) {
+	if !isEnvSet(envUsage) {
+		return
+	}
+
+	var tokensPerSec float64
+	switch {
+	case usage.EvalDuration > 0:
+		tokensPerSec = float64(usage.CompletionTokens) / usage.EvalDuration.Seconds()
+	case elapsed > 0:
+		tokensPerSec = float64(usage.CompletionTokens) / elapsed.Seconds()
+	}
+
+	costStr := "n/a"
+	if apiKey := strings.TrimSpace(os.Getenv(envAPIKey)); apiKey != "" && model != "" {
+		if pricing, ok := pricingFor(model, apiKey, verbose); ok {
+			cost := float64(usage.PromptTokens)*pricing.PromptPrice + float64(usage.CompletionTokens)*pricing.CompletionPrice
+			costStr = fmt.Sprintf("$%.6f", cost)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "[usage] provider=%s model=%s prompt=%d completion=%d total=%d tokens/sec=%.2f cost=%s\n",
+		providerName, model, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, tokensPerSec, costStr)
+}
+
+// pricingFor looks up model's per-token pricing, refreshing the on-disk cache
+// from OpenRouter's /models endpoint if it's missing or older than
+// pricingCacheTTL. A stale cache is used as a best-effort fallback if a
+// refresh fails (e.g. no network); with no readable cache, no pricing is
+// returned.
+func pricingFor(model, apiKey string, verbose bool) (modelPricing, bool) {
+	cache := readPricingCache()
+	if cache == nil || time.Since(time.Unix(cache.FetchedAt, 0)) > pricingCacheTTL {
+		fresh, err := refreshPricingCache(apiKey, verbose)
+		if err != nil {
+			if verbose {
+				log.Printf("[DEBUG] failed to refresh pricing cache: %v", err)
+			}
+			if cache == nil {
+				return modelPricing{}, false
+			}
+		} else {
+			cache = fresh
+		}
+	}
+	p, ok := cache.Models[model]
+	return p, ok
+}
+
+// readPricingCache loads the cache file, returning nil if it doesn't exist or
+// can't be parsed.
+func readPricingCache() *pricingCache {
+	path, err := pricingCachePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cache pricingCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil
+	}
+	return &cache
+}
+
+// refreshPricingCache fetches OpenRouter's current model pricing and writes
+// it to the cache file.
+func refreshPricingCache(apiKey string, verbose bool) (*pricingCache, error) {
+	models, err := fetchOpenRouterModels(apiKey, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &pricingCache{FetchedAt: time.Now().Unix(), Models: map[string]modelPricing{}}
+	for _, m := range models {
+		prompt, _ := strconv.ParseFloat(m.PromptPrice, 64)
+		completion, _ := strconv.ParseFloat(m.CompletionPrice, 64)
+		cache.Models[m.ID] = modelPricing{PromptPrice: prompt, CompletionPrice: completion}
+	}
+
+	path, err := pricingCachePath()
+	if err != nil {
+		return cache, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return cache, nil
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err == nil {
+		_ = os.WriteFile(path, data, 0644)
+	}
+	return cache, nil
+}
+
+func pricingCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(homeDir, pricingCacheFile), nil
+}